@@ -11,6 +11,9 @@ const configsDir = "configs"
 // @version 1.0
 // @description API for managing subscriptions
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 
 func main() {
 	app.Run(configsDir)
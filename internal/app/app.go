@@ -11,10 +11,18 @@ import (
 
 	"github.com/scmbr/subscription-aggregator/internal/config"
 	"github.com/scmbr/subscription-aggregator/internal/delivery/http/handler"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/event"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/notify"
 	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/scheduler"
 	"github.com/scmbr/subscription-aggregator/internal/server"
 	"github.com/scmbr/subscription-aggregator/internal/service"
+	"github.com/scmbr/subscription-aggregator/internal/webhook"
+	"github.com/scmbr/subscription-aggregator/pkg/cursor"
 	"github.com/scmbr/subscription-aggregator/pkg/database/postgres"
+	"github.com/scmbr/subscription-aggregator/pkg/jwt"
 	"github.com/scmbr/subscription-aggregator/pkg/logger"
 )
 
@@ -45,8 +53,47 @@ func Run(configsDir string) {
 		"database is connected": db.DB.Ping() == nil,
 	})
 	repository := repository.NewRepository(db)
-	service := service.NewService(repository)
-	handler := handler.NewHandler(service)
+	var notifier notify.Notifier = notify.NewLogNotifier()
+	if cfg.Reminders.Notifier == "smtp" {
+		notifier = notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.Reminders.SMTP.Host,
+			Port:     cfg.Reminders.SMTP.Port,
+			Username: cfg.Reminders.SMTP.Username,
+			Password: cfg.Reminders.SMTP.Password,
+			From:     cfg.Reminders.SMTP.From,
+			To:       cfg.Reminders.SMTP.To,
+			Timeout:  cfg.Reminders.SMTP.Timeout,
+		})
+	}
+	exchangeRates := exchange.NewStaticRates(cfg.Currency.Default, cfg.Currency.Rates)
+	cursors, err := cursor.NewSigner(cfg.Pagination.CursorSecret)
+	if err != nil {
+		logger.Error("failed to initialize cursor signer, CURSOR_SECRET must be set", err, nil)
+		return
+	}
+	service := service.NewService(service.Deps{
+		Repos:           repository,
+		ExchangeRates:   exchangeRates,
+		DefaultCurrency: cfg.Currency.Default,
+		Cursors:         cursors,
+		Notifier:        notifier,
+		ReminderDays:    cfg.Reminders.DaysAhead,
+		Webhooks:        webhook.NewClient(cfg.Webhooks.Timeout),
+		WebhookRetry: domain.RetryPolicy{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Backoff:     cfg.Webhooks.Backoff,
+			MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		},
+	})
+	keys, err := loadKeySet(cfg.Auth)
+	if err != nil {
+		logger.Error("failed to load jwt keys, JWT_JWKS or JWT_JWKS_FILE must be set", err, map[string]interface{}{
+			"jwks_file": cfg.Auth.JWKSFile,
+		})
+		return
+	}
+	tokens := jwt.NewVerifier(keys, cfg.Auth.Issuer, cfg.Auth.Audience)
+	handler := handler.NewHandler(service, tokens, cfg.Auth.AdminRole)
 	server := server.NewServer(cfg, handler.Init())
 	go func() {
 		if err := server.Run(); !errors.Is(err, http.ErrServerClosed) {
@@ -54,9 +101,75 @@ func Run(configsDir string) {
 		}
 	}()
 	logger.Info("server started", nil)
+
+	jobs := scheduler.New()
+	if cfg.Reminders.Enabled && cfg.Reminders.Interval > 0 {
+		jobs.Add("reminders", cfg.Reminders.Interval, func(ctx context.Context) error {
+			sent, err := service.Reminder.SendReminders(ctx, time.Now())
+			if sent > 0 {
+				logger.Info("reminders sent", map[string]interface{}{
+					"count": sent,
+				})
+			}
+			return err
+		})
+	}
+	if cfg.Webhooks.Enabled && cfg.Webhooks.Interval > 0 {
+		jobs.Add("webhooks", cfg.Webhooks.Interval, func(ctx context.Context) error {
+			delivered, err := service.Webhook.DeliverWebhooks(ctx)
+			if delivered > 0 {
+				logger.Info("webhooks delivered", map[string]interface{}{
+					"count": delivered,
+				})
+			}
+			return err
+		})
+	}
+	if cfg.Outbox.Interval > 0 {
+		publishers := make(event.Publishers, 0, len(cfg.Outbox.Publishers))
+		for _, name := range cfg.Outbox.Publishers {
+			switch name {
+			case "log":
+				publishers = append(publishers, event.NewLogPublisher())
+			case "webhook":
+				publishers = append(publishers, service.Webhook)
+			default:
+				logger.Error("unknown event publisher", errors.New("unknown publisher"), map[string]interface{}{
+					"publisher": name,
+				})
+			}
+		}
+		outbox := &relay{
+			outbox:      repository.Outbox,
+			publisher:   publishers,
+			batchSize:   cfg.Outbox.BatchSize,
+			maxAttempts: cfg.Outbox.MaxAttempts,
+			retention:   cfg.Outbox.Retention,
+		}
+		jobs.Add("outbox relay", cfg.Outbox.Interval, outbox.run)
+	}
+	if cfg.Outbox.RenewalsInterval > 0 {
+		jobs.Add("renewal events", cfg.Outbox.RenewalsInterval, func(ctx context.Context) error {
+			_, err := service.Subscription.PublishRenewals(ctx, time.Now())
+			return err
+		})
+	}
+	if cfg.Purge.Interval > 0 && cfg.Purge.Retention > 0 {
+		jobs.Add("subscription purge", cfg.Purge.Interval, func(ctx context.Context) error {
+			purged, err := service.Subscription.PurgeSubscriptions(ctx, time.Now().Add(-cfg.Purge.Retention))
+			if purged > 0 {
+				logger.Info("deleted subscriptions purged", map[string]interface{}{
+					"count": purged,
+				})
+			}
+			return err
+		})
+	}
+	jobs.Start(context.Background())
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
+	jobs.Stop()
 	const timeout = 5 * time.Second
 	ctx, shutdown := context.WithTimeout(context.Background(), timeout)
 	defer shutdown()
@@ -64,3 +177,15 @@ func Run(configsDir string) {
 		logger.Error("failed to stop server", err, nil)
 	}
 }
+
+// loadKeySet reads the key set given inline, or else from a file. There is no
+// fallback key: the service refuses to start without one.
+func loadKeySet(cfg config.AuthConfig) (*jwt.KeySet, error) {
+	switch {
+	case cfg.JWKS != "":
+		return jwt.ParseKeySet([]byte(cfg.JWKS))
+	case cfg.JWKSFile != "":
+		return jwt.LoadKeySet(cfg.JWKSFile)
+	}
+	return nil, jwt.ErrNoKeySource
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/event"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+const (
+	// defaultRelayBatchSize is used when no batch size is configured.
+	defaultRelayBatchSize = 100
+	// defaultRelayMaxAttempts is used when no maximum number of attempts
+	// is configured.
+	defaultRelayMaxAttempts = 10
+)
+
+// relay hands the events recorded in the outbox to the publisher. Several
+// instances may run at once: each locks its own batch of events. An event is
+// marked as published only after the publisher accepts it, so it is
+// published at least once, unless it fails maxAttempts times and is parked.
+type relay struct {
+	outbox      repository.OutboxRepository
+	publisher   event.Publisher
+	batchSize   int
+	maxAttempts int
+	// retention is how long published events are kept, which also bounds
+	// how long events recorded again are recognized as duplicates.
+	retention time.Duration
+}
+
+// run publishes batches of events until the outbox is drained, then prunes
+// the events published before the retention period.
+func (r *relay) run(ctx context.Context) error {
+	batchSize := r.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultRelayBatchSize
+	}
+	maxAttempts := r.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRelayMaxAttempts
+	}
+	for {
+		published, err := r.outbox.Relay(ctx, batchSize, maxAttempts, func(e *domain.OutboxEvent) error {
+			return r.publisher.Publish(ctx, e)
+		})
+		if err != nil {
+			return err
+		}
+		if published < batchSize {
+			break
+		}
+	}
+	if r.retention <= 0 {
+		return nil
+	}
+	pruned, err := r.outbox.Prune(ctx, time.Now().Add(-r.retention))
+	if pruned > 0 {
+		logger.Debug("outbox events pruned", map[string]interface{}{
+			"count": pruned,
+		})
+	}
+	return err
+}
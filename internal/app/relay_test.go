@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/event"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+)
+
+// memoryOutbox relays events the way OutboxRepo does, without a database.
+type memoryOutbox struct {
+	repository.OutboxRepository
+	events []*domain.OutboxEvent
+	pruned bool
+}
+
+func (o *memoryOutbox) Relay(ctx context.Context, limit, maxAttempts int, publish func(*domain.OutboxEvent) error) (int, error) {
+	published := 0
+	var errs []error
+	for _, e := range o.events {
+		if limit == 0 {
+			break
+		}
+		if e.PublishedAt != nil || e.ParkedAt != nil {
+			continue
+		}
+		limit--
+		if err := publish(e); err != nil {
+			errs = append(errs, err)
+			e.Fail(time.Now(), maxAttempts)
+			if e.ParkedAt == nil {
+				break
+			}
+			continue
+		}
+		now := time.Now()
+		e.Attempts++
+		e.PublishedAt = &now
+		published++
+	}
+	return published, errors.Join(errs...)
+}
+
+func (o *memoryOutbox) Prune(ctx context.Context, before time.Time) (int64, error) {
+	o.pruned = true
+	return 0, nil
+}
+
+// poisonPublisher refuses to publish one event.
+type poisonPublisher struct {
+	id string
+}
+
+func (p poisonPublisher) Publish(ctx context.Context, e *domain.OutboxEvent) error {
+	if e.Id == p.id {
+		return errors.New("malformed payload")
+	}
+	return nil
+}
+
+func outboxFixture(n int) *memoryOutbox {
+	outbox := &memoryOutbox{}
+	for i := 0; i < n; i++ {
+		outbox.events = append(outbox.events, &domain.OutboxEvent{
+			Id:         fmt.Sprintf("event-%d", i),
+			Type:       domain.EventSubscriptionCreated,
+			OccurredAt: time.Date(2025, time.July, 1, 12, i, 0, 0, time.UTC),
+		})
+	}
+	return outbox
+}
+
+func publishedIDs(publisher *event.MemoryPublisher) []string {
+	ids := make([]string, 0)
+	for _, e := range publisher.Events() {
+		ids = append(ids, e.Id)
+	}
+	return ids
+}
+
+func TestRelayDrainsOutboxInOrder(t *testing.T) {
+	outbox := outboxFixture(5)
+	publisher := event.NewMemoryPublisher()
+	r := &relay{outbox: outbox, publisher: publisher, batchSize: 2, retention: time.Hour}
+
+	if err := r.run(context.Background()); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	want := []string{"event-0", "event-1", "event-2", "event-3", "event-4"}
+	if got := publishedIDs(publisher); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+	if !outbox.pruned {
+		t.Error("published events were not pruned")
+	}
+}
+
+func TestRelayParksPoisonEvent(t *testing.T) {
+	outbox := outboxFixture(3)
+	publisher := event.NewMemoryPublisher()
+	r := &relay{
+		outbox:      outbox,
+		publisher:   event.Publishers{poisonPublisher{id: "event-1"}, publisher},
+		batchSize:   10,
+		maxAttempts: 3,
+	}
+
+	for run := 1; run < 3; run++ {
+		if err := r.run(context.Background()); err == nil {
+			t.Fatalf("run %d: error = nil, want the publish failure", run)
+		}
+		if got := publishedIDs(publisher); fmt.Sprint(got) != "[event-0]" {
+			t.Fatalf("run %d: published %v, want the events after the failing one held back", run, got)
+		}
+	}
+	if err := r.run(context.Background()); err == nil {
+		t.Fatal("run 3: error = nil, want the publish failure")
+	}
+	poison := outbox.events[1]
+	if poison.ParkedAt == nil || poison.Attempts != 3 {
+		t.Fatalf("poison event: attempts %d, parked at %v, want parked after 3 attempts", poison.Attempts, poison.ParkedAt)
+	}
+	if got := publishedIDs(publisher); fmt.Sprint(got) != "[event-0 event-2]" {
+		t.Errorf("published %v, want [event-0 event-2]", got)
+	}
+
+	publisher.Reset()
+	if err := r.run(context.Background()); err != nil {
+		t.Fatalf("run after parking: error = %v", err)
+	}
+	if got := publisher.Events(); len(got) != 0 {
+		t.Errorf("parked event published again: %v", publishedIDs(publisher))
+	}
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+// Metadata describes the request a change is made in.
+type Metadata struct {
+	RequestID string
+	// OnBehalfOf is the end user a service caller claims to act for. It is
+	// recorded for API key callers only.
+	OnBehalfOf string
+}
+
+type metadataKey struct{}
+
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+func FromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+	return metadata
+}
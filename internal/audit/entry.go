@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// systemActor is recorded for changes made outside of an API request.
+const systemActor = "system"
+
+// Snapshot is the state of a subscription recorded in the audit log.
+type Snapshot struct {
+	Id            string        `json:"subscription_id"`
+	ServiceName   string        `json:"service_name"`
+	ServiceID     *string       `json:"service_id"`
+	Price         int           `json:"price"`
+	Currency      string        `json:"currency"`
+	BillingPeriod string        `json:"billing_period"`
+	UserID        string        `json:"user_id"`
+	StartDate     time.Time     `json:"start_date"`
+	EndDate       *time.Time    `json:"end_date"`
+	TrialEndDate  *time.Time    `json:"trial_end_date"`
+	Status        string        `json:"status"`
+	CancelledAt   *time.Time    `json:"cancelled_at"`
+	Tags          []string      `json:"tags"`
+	Members       []Member      `json:"members"`
+	Prices        []PriceChange `json:"prices"`
+	Pauses        []Pause       `json:"pauses"`
+}
+
+type Member struct {
+	UserID      string `json:"user_id"`
+	Weight      *int   `json:"weight,omitempty"`
+	FixedAmount *int   `json:"fixed_amount,omitempty"`
+}
+
+type PriceChange struct {
+	Price         int       `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+type Pause struct {
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// NewSnapshot copies the state of s, so that s can be changed afterwards.
+// It returns nil when s is nil.
+func NewSnapshot(s *domain.Subscription) *Snapshot {
+	if s == nil {
+		return nil
+	}
+	tags := make([]string, 0, len(s.Tags))
+	tags = append(tags, s.Tags...)
+	members := make([]Member, 0, len(s.Members))
+	for _, m := range s.Members {
+		members = append(members, Member{
+			UserID:      m.UserID,
+			Weight:      copyPtr(m.Weight),
+			FixedAmount: copyPtr(m.FixedAmount),
+		})
+	}
+	prices := make([]PriceChange, 0, len(s.Prices))
+	for _, p := range s.Prices {
+		prices = append(prices, PriceChange{
+			Price:         p.Price,
+			EffectiveFrom: p.EffectiveFrom,
+		})
+	}
+	pauses := make([]Pause, 0, len(s.Pauses))
+	for _, p := range s.Pauses {
+		pauses = append(pauses, Pause{
+			StartDate: p.StartDate,
+			EndDate:   copyPtr(p.EndDate),
+		})
+	}
+	return &Snapshot{
+		Id:            s.Id,
+		ServiceName:   s.ServiceName,
+		ServiceID:     copyPtr(s.ServiceID),
+		Price:         s.Price,
+		Currency:      s.Currency,
+		BillingPeriod: string(s.BillingPeriod),
+		UserID:        s.UserID,
+		StartDate:     s.StartDate,
+		EndDate:       copyPtr(s.EndDate),
+		TrialEndDate:  copyPtr(s.TrialEndDate),
+		Status:        string(s.Status),
+		CancelledAt:   copyPtr(s.CancelledAt),
+		Tags:          tags,
+		Members:       members,
+		Prices:        prices,
+		Pauses:        pauses,
+	}
+}
+
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// Change holds the old and new JSON values of a field.
+type Change struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// NewEntry records the change of a subscription from before to after made by
+// the caller in ctx. before is nil for creations and restorations and after
+// for deletions.
+func NewEntry(ctx context.Context, id string, action domain.AuditAction, before, after *Snapshot, occurredAt time.Time) (*domain.AuditEntry, error) {
+	state := after
+	if state == nil {
+		state = before
+	}
+	if state == nil {
+		return nil, fmt.Errorf("audit.NewEntry: no state of the subscription")
+	}
+	beforeJSON, beforeFields, err := encode(before)
+	if err != nil {
+		return nil, fmt.Errorf("audit.NewEntry: %w", err)
+	}
+	afterJSON, afterFields, err := encode(after)
+	if err != nil {
+		return nil, fmt.Errorf("audit.NewEntry: %w", err)
+	}
+	changes, err := json.Marshal(diff(beforeFields, afterFields))
+	if err != nil {
+		return nil, fmt.Errorf("audit.NewEntry: %w", err)
+	}
+
+	entry := &domain.AuditEntry{
+		Id:             id,
+		SubscriptionID: state.Id,
+		UserID:         state.UserID,
+		Action:         action,
+		Actor:          systemActor,
+		Before:         beforeJSON,
+		After:          afterJSON,
+		Changes:        changes,
+		OccurredAt:     occurredAt,
+	}
+	metadata := FromContext(ctx)
+	if metadata.RequestID != "" {
+		entry.RequestID = &metadata.RequestID
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		entry.Actor = actor(identity)
+		if identity.APIKeyID != "" && metadata.OnBehalfOf != "" {
+			entry.OnBehalfOf = &metadata.OnBehalfOf
+		}
+	}
+	return entry, nil
+}
+
+// actor identifies users by their ID and service callers by their API key.
+func actor(identity *auth.Identity) string {
+	if identity.APIKeyID != "" {
+		return "api_key:" + identity.APIKeyID
+	}
+	return "user:" + identity.Subject
+}
+
+// encode returns the JSON of the snapshot and its top-level fields, or nils
+// for a nil snapshot.
+func encode(s *Snapshot) ([]byte, map[string]json.RawMessage, error) {
+	if s == nil {
+		return nil, nil, nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+	return data, fields, nil
+}
+
+// diff returns the fields whose values differ between before and after. A
+// missing field counts as null.
+func diff(before, after map[string]json.RawMessage) map[string]Change {
+	null := json.RawMessage("null")
+	changes := make(map[string]Change)
+	for field, from := range before {
+		to, ok := after[field]
+		if !ok {
+			to = null
+		}
+		if !bytes.Equal(from, to) {
+			changes[field] = Change{From: from, To: to}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok && !bytes.Equal(to, null) {
+			changes[field] = Change{From: null, To: to}
+		}
+	}
+	return changes
+}
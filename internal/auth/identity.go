@@ -0,0 +1,43 @@
+package auth
+
+import "context"
+
+// Identity describes the authenticated caller of the API.
+type Identity struct {
+	// Subject is the ID of the user the caller acts as. It is empty for
+	// service callers authenticated with an API key.
+	Subject string
+	Admin   bool
+	// APIKeyID is the ID of the API key the caller authenticated with.
+	APIKeyID string
+	// Scopes limits what an API key caller may do; nil means no limits.
+	Scopes []string
+}
+
+// AllUsers reports whether the caller may access data of every user.
+func (i *Identity) AllUsers() bool {
+	return i.Admin || i.APIKeyID != ""
+}
+
+func (i *Identity) HasScope(scope string) bool {
+	if i.Scopes == nil {
+		return true
+	}
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok && identity != nil
+}
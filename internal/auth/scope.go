@@ -0,0 +1,15 @@
+package auth
+
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeReportsRead        = "reports:read"
+)
+
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeSubscriptionsRead, ScopeSubscriptionsWrite, ScopeReportsRead:
+		return true
+	}
+	return false
+}
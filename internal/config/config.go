@@ -9,8 +9,15 @@ import (
 
 type (
 	Config struct {
-		Postgres PostgresConfig
-		HTTP     HTTPConfig
+		Postgres   PostgresConfig
+		HTTP       HTTPConfig
+		Currency   CurrencyConfig
+		Pagination PaginationConfig
+		Auth       AuthConfig
+		Reminders  RemindersConfig
+		Webhooks   WebhooksConfig
+		Outbox     OutboxConfig
+		Purge      PurgeConfig
 	}
 	PostgresConfig struct {
 		Username string
@@ -27,6 +34,72 @@ type (
 		WriteTimeout       time.Duration `mapstructure:"writeTimeout"`
 		MaxHeaderMegabytes int           `mapstructure:"maxHeaderBytes"`
 	}
+	AuthConfig struct {
+		// JWKS is the JSON Web Key Set itself and JWKSFile the path to it,
+		// e.g. a mounted secret. One of them must be set.
+		JWKS      string
+		JWKSFile  string `mapstructure:"jwksFile"`
+		Issuer    string `mapstructure:"issuer"`
+		Audience  string `mapstructure:"audience"`
+		AdminRole string `mapstructure:"adminRole"`
+	}
+	PaginationConfig struct {
+		CursorSecret string
+	}
+	RemindersConfig struct {
+		Enabled   bool          `mapstructure:"enabled"`
+		Interval  time.Duration `mapstructure:"interval"`
+		DaysAhead int           `mapstructure:"daysAhead"`
+		// Notifier is either "log" or "smtp".
+		Notifier string     `mapstructure:"notifier"`
+		SMTP     SMTPConfig `mapstructure:"smtp"`
+	}
+	SMTPConfig struct {
+		Host     string `mapstructure:"host"`
+		Port     string `mapstructure:"port"`
+		Username string `mapstructure:"username"`
+		Password string
+		From     string `mapstructure:"from"`
+		// To is the recipient address, with "{user_id}" replaced by the ID
+		// of the user.
+		To      string        `mapstructure:"to"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+	WebhooksConfig struct {
+		Enabled bool `mapstructure:"enabled"`
+		// Interval is how often due deliveries are sent.
+		Interval    time.Duration `mapstructure:"interval"`
+		Timeout     time.Duration `mapstructure:"timeout"`
+		MaxAttempts int           `mapstructure:"maxAttempts"`
+		Backoff     time.Duration `mapstructure:"backoff"`
+		MaxBackoff  time.Duration `mapstructure:"maxBackoff"`
+	}
+	OutboxConfig struct {
+		// Interval is how often the outbox is polled for new events.
+		Interval  time.Duration `mapstructure:"interval"`
+		BatchSize int           `mapstructure:"batchSize"`
+		// MaxAttempts is how many times an event is published before it
+		// is parked.
+		MaxAttempts int `mapstructure:"maxAttempts"`
+		// Retention is how long published events are kept.
+		Retention time.Duration `mapstructure:"retention"`
+		// Publishers lists where events are published: "log" and
+		// "webhook".
+		Publishers []string `mapstructure:"publishers"`
+		// RenewalsInterval is how often renewal events are recorded.
+		RenewalsInterval time.Duration `mapstructure:"renewalsInterval"`
+	}
+	PurgeConfig struct {
+		// Interval is how often deleted subscriptions are purged.
+		Interval time.Duration `mapstructure:"interval"`
+		// Retention is how long deleted subscriptions can be restored
+		// before they are purged.
+		Retention time.Duration `mapstructure:"retention"`
+	}
+	CurrencyConfig struct {
+		Default string             `mapstructure:"default"`
+		Rates   map[string]float64 `mapstructure:"rates"`
+	}
 )
 
 func Init(configsDir string) (*Config, error) {
@@ -50,10 +123,34 @@ func unmarshal(cfg *Config) error {
 	if err := viper.UnmarshalKey("http", &cfg.HTTP); err != nil {
 		return err
 	}
+	if err := viper.UnmarshalKey("currency", &cfg.Currency); err != nil {
+		return err
+	}
+	if err := viper.UnmarshalKey("auth", &cfg.Auth); err != nil {
+		return err
+	}
+	if err := viper.UnmarshalKey("reminders", &cfg.Reminders); err != nil {
+		return err
+	}
+	if err := viper.UnmarshalKey("webhooks", &cfg.Webhooks); err != nil {
+		return err
+	}
+	if err := viper.UnmarshalKey("outbox", &cfg.Outbox); err != nil {
+		return err
+	}
+	if err := viper.UnmarshalKey("purge", &cfg.Purge); err != nil {
+		return err
+	}
 	return nil
 }
 func setFromEnv(cfg *Config) {
 	cfg.Postgres.Username = os.Getenv("POSTGRES_USER")
 	cfg.Postgres.Name = os.Getenv("POSTGRES_DB")
 	cfg.Postgres.Password = os.Getenv("POSTGRES_PASSWORD")
+	cfg.Pagination.CursorSecret = os.Getenv("CURSOR_SECRET")
+	cfg.Reminders.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.Auth.JWKS = os.Getenv("JWT_JWKS")
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		cfg.Auth.JWKSFile = path
+	}
 }
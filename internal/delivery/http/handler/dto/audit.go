@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetAuditLogRequest struct {
+	SubscriptionID *string `form:"subscription_id" binding:"omitempty,uuid"`
+	UserID         *string `form:"user_id" binding:"omitempty,uuid"`
+	// Actor is either "user:<id>" or "api_key:<id>".
+	Actor  *string `form:"actor"`
+	Action *string `form:"action" binding:"omitempty,oneof=create update delete restore"`
+	// From and To are RFC 3339 timestamps; From is inclusive and To
+	// exclusive.
+	From *time.Time `form:"from"`
+	To   *time.Time `form:"to"`
+}
+type AuditEntryResponse struct {
+	Id             string  `json:"audit_id"`
+	SubscriptionID string  `json:"subscription_id"`
+	UserID         string  `json:"user_id"`
+	Action         string  `json:"action"`
+	Actor          string  `json:"actor"`
+	OnBehalfOf     *string `json:"on_behalf_of"`
+	RequestID      *string `json:"request_id"`
+	// Before is null for creations and restorations and After for
+	// deletions.
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+	// Changes maps every changed field to its old and new values, e.g.
+	// {"price": {"from": 400, "to": 500}}.
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+type GetAuditLogResponse struct {
+	Total   int                  `json:"total"`
+	Entries []AuditEntryResponse `json:"entries"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+// BatchUpdateSubscriptionRequest updates the subscription with the ID. When
+// Version is set, the item fails unless the subscription still has this
+// version, like If-Match does for a single update.
+type BatchUpdateSubscriptionRequest struct {
+	Id      string `json:"subscription_id" binding:"required,uuid"`
+	Version *int64 `json:"version" binding:"omitempty,min=1"`
+	UpdateSubscriptionRequest
+}
+type BatchDeleteSubscriptionsRequest struct {
+	Ids []string `json:"subscription_ids" binding:"required"`
+}
+type BatchResponse struct {
+	// Committed is false when an atomic batch was rolled back.
+	Committed bool                `json:"committed"`
+	Results   []BatchItemResponse `json:"results"`
+}
+
+// BatchItemResponse is the result of the item at the same position in the
+// request.
+type BatchItemResponse struct {
+	Id      string `json:"subscription_id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+type ImportResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Committed is true when all rows were valid and have been created.
+	Committed bool                `json:"committed"`
+	Total     int                 `json:"total"`
+	Invalid   int                 `json:"invalid"`
+	Rows      []ImportRowResponse `json:"rows"`
+}
+
+// ImportRowResponse reports a row by its line number in the file.
+type ImportRowResponse struct {
+	Row   int    `json:"row"`
+	Id    string `json:"subscription_id,omitempty"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// CalendarTokenResponse carries the plaintext feed token, which is never
+// returned again, and the feed URL to subscribe to.
+type CalendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
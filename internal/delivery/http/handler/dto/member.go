@@ -0,0 +1,17 @@
+package dto
+
+// AddMemberRequest sets either the weight of the member in splitting a charge
+// or a fixed amount the member pays of every charge.
+type AddMemberRequest struct {
+	UserID      string `json:"user_id" binding:"required,uuid"`
+	Weight      *int   `json:"weight" binding:"omitempty,gt=0"`
+	FixedAmount *int   `json:"fixed_amount" binding:"omitempty,gte=0"`
+}
+type MemberResponse struct {
+	UserID      string `json:"user_id"`
+	Weight      *int   `json:"weight"`
+	FixedAmount *int   `json:"fixed_amount"`
+}
+type GetMembersResponse struct {
+	Members []MemberResponse `json:"members"`
+}
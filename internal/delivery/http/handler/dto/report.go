@@ -0,0 +1,25 @@
+package dto
+
+type GetReportRequest struct {
+	// GroupBy is a comma-separated list of service_name, user_id and month.
+	GroupBy     string     `form:"group_by"`
+	UserID      *string    `form:"user_id" binding:"omitempty,uuid"`
+	ServiceName *string    `form:"service_name"`
+	StartDate   *MonthYear `form:"start_date" binding:"required"`
+	EndDate     *MonthYear `form:"end_date" binding:"required"`
+	Currency    *string    `form:"currency" binding:"omitempty,iso4217"`
+}
+type GetReportResponse struct {
+	Currency   string              `json:"currency"`
+	GroupBy    []string            `json:"group_by"`
+	TotalPrice int                 `json:"total_price"`
+	Rows       []ReportRowResponse `json:"rows"`
+}
+type ReportRowResponse struct {
+	ServiceName *string    `json:"service_name,omitempty"`
+	UserID      *string    `json:"user_id,omitempty"`
+	Month       *MonthYear `json:"month,omitempty"`
+	Months      int        `json:"months"`
+	Charges     int        `json:"charges"`
+	TotalPrice  int        `json:"total_price"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type CreateServiceRequest struct {
+	Name         string   `json:"name" binding:"required,max=30"`
+	Aliases      []string `json:"aliases" binding:"omitempty,dive,max=100"`
+	Category     *string  `json:"category" binding:"omitempty,max=50"`
+	DefaultPrice *int     `json:"default_price" binding:"omitempty,gte=0"`
+	LogoURL      *string  `json:"logo_url" binding:"omitempty,url"`
+}
+type UpdateServiceRequest struct {
+	Name *string `json:"name" binding:"omitempty,max=30"`
+	// Aliases replaces all aliases of the service when set.
+	Aliases      *[]string `json:"aliases" binding:"omitempty,dive,max=100"`
+	Category     *string   `json:"category" binding:"omitempty,max=50"`
+	DefaultPrice *int      `json:"default_price" binding:"omitempty,gte=0"`
+	LogoURL      *string   `json:"logo_url" binding:"omitempty,url"`
+}
+type ServiceResponse struct {
+	Id           string    `json:"service_id"`
+	Name         string    `json:"name"`
+	Aliases      []string  `json:"aliases"`
+	Category     *string   `json:"category"`
+	DefaultPrice *int      `json:"default_price"`
+	LogoURL      *string   `json:"logo_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+type GetAllServicesResponse struct {
+	Services []ServiceResponse `json:"services"`
+}
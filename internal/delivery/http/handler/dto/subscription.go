@@ -1,41 +1,169 @@
 package dto
 
+import "time"
+
 type CreateSubscriptionRequest struct {
-	ServiceName string     `json:"service_name" binding:"required"`
-	Price       int        `json:"price" binding:"required,gte=0"`
-	UserID      string     `json:"user_id" binding:"required,uuid4"`
-	StartDate   MonthYear  `json:"start_date" binding:"required"`
-	EndDate     *MonthYear `json:"end_date" binding:"omitempty"`
+	ServiceName   string     `json:"service_name" binding:"required,max=30"`
+	Price         int        `json:"price" binding:"required,gte=0"`
+	Currency      string     `json:"currency" binding:"omitempty,iso4217"`
+	BillingPeriod string     `json:"billing_period" binding:"omitempty,oneof=weekly monthly quarterly yearly"`
+	UserID        string     `json:"user_id" binding:"required,uuid4"`
+	StartDate     MonthYear  `json:"start_date" binding:"required"`
+	EndDate       *MonthYear `json:"end_date" binding:"omitempty"`
+	// TrialEndDate is the last day of the free trial, which is not charged.
+	TrialEndDate *Date    `json:"trial_end_date" binding:"omitempty"`
+	Tags         []string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 type CreateSubscriptionResponse struct {
 	Id string `json:"subscription_id"`
 }
+type GetAllSubscriptionsRequest struct {
+	UserID            *string    `form:"user_id" binding:"omitempty,uuid"`
+	ServiceName       *string    `form:"service_name"`
+	ServiceNamePrefix *string    `form:"service_name_prefix"`
+	MinPrice          *int       `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice          *int       `form:"max_price" binding:"omitempty,gte=0"`
+	ActiveAt          *MonthYear `form:"active_at"`
+	StartDateFrom     *MonthYear `form:"start_date_from"`
+	StartDateTo       *MonthYear `form:"start_date_to"`
+	EndDateFrom       *MonthYear `form:"end_date_from"`
+	EndDateTo         *MonthYear `form:"end_date_to"`
+	NoEndDate         *bool      `form:"no_end_date"`
+	TrialEndingWithin *int       `form:"trial_ending_within" binding:"omitempty,gte=0"`
+	Tag               *string    `form:"tag"`
+	IncludeDeleted    bool       `form:"include_deleted"`
+	// Sort is a comma-separated list of fields, each optionally prefixed
+	// with "-" for descending order, e.g. "price,-start_date".
+	Sort string `form:"sort"`
+}
 type GetAllSubscriptionsResponse struct {
-	Total         int                       `json:"total"`
+	// Total is omitted in cursor mode.
+	Total         *int                      `json:"total,omitempty"`
 	Subscriptions []GetSubscriptionResponse `json:"subscriptions"`
+	NextCursor    *string                   `json:"next_cursor,omitempty"`
 }
 type GetSubscriptionResponse struct {
-	Id          string     `json:"subscription_id"`
-	ServiceName string     `json:"service_name"`
-	Price       int        `json:"price"`
-	UserID      string     `json:"user_id"`
-	StartDate   MonthYear  `json:"start_date"`
-	EndDate     *MonthYear `json:"end_date"`
+	Id            string     `json:"subscription_id"`
+	ServiceName   string     `json:"service_name"`
+	ServiceID     *string    `json:"service_id"`
+	Price         int        `json:"price"`
+	Currency      string     `json:"currency"`
+	BillingPeriod string     `json:"billing_period"`
+	MonthlyPrice  int        `json:"monthly_price"`
+	UserID        string     `json:"user_id"`
+	StartDate     MonthYear  `json:"start_date"`
+	EndDate       *MonthYear `json:"end_date"`
+	TrialEndDate  *Date      `json:"trial_end_date"`
+	// Status is one of active, paused, cancelled, expired.
+	Status      string           `json:"status"`
+	CancelledAt *time.Time       `json:"cancelled_at"`
+	Pauses      []PauseResponse  `json:"pauses"`
+	Tags        []string         `json:"tags"`
+	Members     []MemberResponse `json:"members"`
+	// DeletedAt is set for deleted subscriptions only.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version is incremented on every change and is also returned in the
+	// ETag header.
+	Version int64 `json:"version"`
+}
+type PauseResponse struct {
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
 }
 type UpdateSubscriptionRequest struct {
-	ServiceName *string    `json:"service_name"  binding:"omitempty"`
-	Price       *int       `json:"price"  binding:"omitempty,gte=0"`
-	UserID      *string    `json:"user_id"  binding:"omitempty"`
-	StartDate   *MonthYear `json:"start_date"  binding:"omitempty"`
-	EndDate     *MonthYear `json:"end_date"  binding:"omitempty"`
+	ServiceName   *string    `json:"service_name"  binding:"omitempty,max=30"`
+	Price         *int       `json:"price"  binding:"omitempty,gte=0"`
+	Currency      *string    `json:"currency"  binding:"omitempty,iso4217"`
+	BillingPeriod *string    `json:"billing_period"  binding:"omitempty,oneof=weekly monthly quarterly yearly"`
+	UserID        *string    `json:"user_id"  binding:"omitempty,uuid4"`
+	StartDate     *MonthYear `json:"start_date"  binding:"omitempty"`
+	EndDate       *MonthYear `json:"end_date"  binding:"omitempty"`
+	// TrialEndDate is the last day of the free trial, which is not charged.
+	TrialEndDate *Date `json:"trial_end_date"  binding:"omitempty"`
+	// PriceEffectiveFrom is the month from which Price applies, the current
+	// month or the start date, whichever is later, by default. It cannot be
+	// in the past.
+	PriceEffectiveFrom *MonthYear `json:"price_effective_from"  binding:"omitempty"`
+	// Tags replaces all tags of the subscription when set.
+	Tags *[]string `json:"tags"  binding:"omitempty,max=20,dive,max=50"`
+}
+type SchedulePriceRequest struct {
+	Price         int       `json:"price" binding:"required,gte=0"`
+	EffectiveFrom MonthYear `json:"effective_from" binding:"required"`
+}
+type GetSubscriptionPricesResponse struct {
+	Prices []SubscriptionPriceResponse `json:"prices"`
+}
+type SubscriptionPriceResponse struct {
+	Price         int       `json:"price"`
+	EffectiveFrom MonthYear `json:"effective_from"`
 }
 
 type GetTotalPriceRequest struct {
 	UserID      *string    `form:"user_id"`
 	ServiceName *string    `form:"service_name"`
+	Tag         *string    `form:"tag"`
 	StartDate   *MonthYear `form:"start_date" binding:"required"`
 	EndDate     *MonthYear `form:"end_date" binding:"required"`
+	Currency    *string    `form:"currency" binding:"omitempty,iso4217"`
 }
 type GetTotalPriceResponse struct {
-	TotalPrice int `json:"total_price"`
+	TotalPrice    int                        `json:"total_price"`
+	Currency      string                     `json:"currency"`
+	Breakdown     []CurrencyTotalResponse    `json:"breakdown"`
+	ByTag         []TagTotalResponse         `json:"by_tag"`
+	Subscriptions []SubscriptionCostResponse `json:"subscriptions"`
+	// Balance is returned when the total is calculated for a single user.
+	Balance *BalanceResponse `json:"balance,omitempty"`
+}
+
+// BalanceResponse compares what a user paid for the subscriptions they pay
+// for with their own shares.
+type BalanceResponse struct {
+	Paid       int `json:"paid"`
+	OwedToUser int `json:"owed_to_user"`
+	OwedByUser int `json:"owed_by_user"`
+	// Net is positive when other members owe the user.
+	Net int `json:"net"`
+}
+
+// TagTotalResponse is the spend on subscriptions with a tag. A subscription
+// with several tags counts towards each of them.
+type TagTotalResponse struct {
+	Tag        string                  `json:"tag"`
+	TotalPrice int                     `json:"total_price"`
+	Breakdown  []CurrencyTotalResponse `json:"breakdown"`
+}
+type CurrencyTotalResponse struct {
+	Currency       string `json:"currency"`
+	TotalPrice     int    `json:"total_price"`
+	ConvertedPrice int    `json:"converted_price"`
+}
+type SubscriptionCostResponse struct {
+	Id            string   `json:"subscription_id"`
+	ServiceName   string   `json:"service_name"`
+	Price         int      `json:"price"`
+	Currency      string   `json:"currency"`
+	BillingPeriod string   `json:"billing_period"`
+	Tags          []string `json:"tags"`
+	Months        int      `json:"months"`
+	Charges       int      `json:"charges"`
+	Cost          int      `json:"cost"`
+}
+
+type GetUserMonthlySpendRequest struct {
+	Currency *string `form:"currency" binding:"omitempty,iso4217"`
+}
+type GetUserSubscriptionsCountResponse struct {
+	Total  int `json:"total"`
+	Active int `json:"active"`
+}
+type GetUserTagsResponse struct {
+	Tags []string `json:"tags"`
+}
+type GetUserMonthlySpendResponse struct {
+	MonthlySpend  int                     `json:"monthly_spend"`
+	Currency      string                  `json:"currency"`
+	Subscriptions int                     `json:"subscriptions"`
+	Breakdown     []CurrencyTotalResponse `json:"breakdown"`
 }
@@ -20,6 +20,61 @@ func (m *MonthYear) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalParam allows MonthYear to be bound from query and form parameters.
+func (m *MonthYear) UnmarshalParam(param string) error {
+	t, err := time.Parse("01-2006", param)
+	if err != nil {
+		return err
+	}
+	m.Time = t
+	return nil
+}
+
 func (m MonthYear) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`"%02d-%d"`, m.Month(), m.Year())), nil
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// String formats m as MM-YYYY.
+func (m MonthYear) String() string {
+	return fmt.Sprintf("%02d-%d", m.Month(), m.Year())
+}
+
+// TimePtr returns the underlying time of m, or nil when m is nil.
+func (m *MonthYear) TimePtr() *time.Time {
+	if m == nil {
+		return nil
+	}
+	return &m.Time
+}
+
+// Date is a calendar day in the YYYY-MM-DD format.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	t, err := time.Parse(time.DateOnly, str)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// String formats d as YYYY-MM-DD.
+func (d Date) String() string {
+	return d.Format(time.DateOnly)
+}
+
+// TimePtr returns the underlying time of d, or nil when d is nil.
+func (d *Date) TimePtr() *time.Time {
+	if d == nil {
+		return nil
+	}
+	return &d.Time
 }
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	URL string `json:"url" binding:"required,url,max=2048"`
+	// Secret signs the deliveries; a random one is generated when omitted.
+	Secret     *string  `json:"secret" binding:"omitempty,min=16,max=100"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=subscription.created subscription.updated subscription.deleted subscription.restored subscription.renewed"`
+}
+type WebhookResponse struct {
+	Id         string    `json:"webhook_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateWebhookResponse includes the signing secret, which is shown only
+// once.
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+type GetAllWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+type WebhookDeliveryResponse struct {
+	Id             string          `json:"delivery_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+type GetWebhookDeliveriesResponse struct {
+	Total      int                       `json:"total"`
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
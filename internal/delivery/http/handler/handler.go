@@ -4,27 +4,33 @@ import (
 	"github.com/gin-gonic/gin"
 	v1 "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/v1"
 	"github.com/scmbr/subscription-aggregator/internal/service"
+	"github.com/scmbr/subscription-aggregator/pkg/jwt"
 )
 
 type Handler struct {
-	service *service.Service
+	service   *service.Service
+	tokens    *jwt.Verifier
+	adminRole string
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service *service.Service, tokens *jwt.Verifier, adminRole string) *Handler {
 	return &Handler{
-		service: service,
+		service:   service,
+		tokens:    tokens,
+		adminRole: adminRole,
 	}
 }
 
 func (h *Handler) Init() *gin.Engine {
 	router := gin.Default()
-	router.Use(gin.Recovery(), gin.Logger())
+	router.Use(gin.Recovery(), gin.Logger(), requestMetadata)
 	h.initAPI(router)
 	return router
 }
 func (h *Handler) initAPI(router *gin.Engine) {
 	handlerV1 := v1.NewHandler(h.service)
-	api := router.Group("/api")
+	handlerV1.InitPublic(router.Group("/api"))
+	api := router.Group("/api", h.authenticate)
 	{
 		handlerV1.Init(api)
 	}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/scmbr/subscription-aggregator/internal/audit"
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerScheme        = "Bearer"
+	apiKeyScheme        = "ApiKey"
+
+	requestIDHeader = "X-Request-ID"
+	// onBehalfOfHeader names the end user a service caller acts for.
+	onBehalfOfHeader = "X-On-Behalf-Of"
+	// maxHeaderValueLength limits the values of the headers above that are
+	// recorded in the audit log.
+	maxHeaderValueLength = 255
+
+	userIDCtx = "userID"
+)
+
+// authenticate accepts either a bearer JWT of a user or an API key of a
+// service caller and stores the caller identity in the request context.
+func (h *Handler) authenticate(c *gin.Context) {
+	scheme, credentials, ok := strings.Cut(c.GetHeader(authorizationHeader), " ")
+	credentials = strings.TrimSpace(credentials)
+	if !ok || credentials == "" {
+		unauthorized(c)
+		return
+	}
+
+	var identity *auth.Identity
+	switch {
+	case strings.EqualFold(scheme, bearerScheme):
+		identity = h.identityFromToken(credentials)
+	case strings.EqualFold(scheme, apiKeyScheme):
+		var err error
+		identity, err = h.service.APIKey.Authenticate(c.Request.Context(), credentials)
+		if err != nil && !errors.Is(err, service.ErrInvalidAPIKey) {
+			logger.Error("error occurred while authenticating api key", err, nil)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, handler_dto.ErrorResponse{Message: "something went wrong"})
+			return
+		}
+	}
+	if identity == nil {
+		unauthorized(c)
+		return
+	}
+
+	c.Set(userIDCtx, identity.Subject)
+	c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
+	c.Next()
+}
+
+func (h *Handler) identityFromToken(token string) *auth.Identity {
+	claims, err := h.tokens.Verify(token, time.Now())
+	if err != nil {
+		return nil
+	}
+	identity := &auth.Identity{
+		Subject: claims.Subject,
+	}
+	roles := append(claims.Strings("role"), claims.Strings("roles")...)
+	for _, role := range roles {
+		if role == h.adminRole {
+			identity.Admin = true
+		}
+	}
+	return identity
+}
+
+// requestMetadata takes the request ID from the X-Request-ID header or
+// generates one, echoes it in the response and stores it in the request
+// context along with the X-On-Behalf-Of header. Values that are too long are
+// ignored.
+func requestMetadata(c *gin.Context) {
+	requestID := strings.TrimSpace(c.GetHeader(requestIDHeader))
+	if requestID == "" || len(requestID) > maxHeaderValueLength {
+		requestID = uuid.NewString()
+	}
+	onBehalfOf := strings.TrimSpace(c.GetHeader(onBehalfOfHeader))
+	if len(onBehalfOf) > maxHeaderValueLength {
+		onBehalfOf = ""
+	}
+	c.Header(requestIDHeader, requestID)
+	c.Request = c.Request.WithContext(audit.WithMetadata(c.Request.Context(), audit.Metadata{
+		RequestID:  requestID,
+		OnBehalfOf: onBehalfOf,
+	}))
+	c.Next()
+}
+
+func unauthorized(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, handler_dto.ErrorResponse{Message: "unauthorized"})
+}
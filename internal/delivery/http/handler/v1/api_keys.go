@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+func (h *Handler) initAdminRoutes(api *gin.RouterGroup) {
+	apiKeys := api.Group("/admin/api-keys")
+	{
+		apiKeys.POST("", h.createAPIKey)
+		apiKeys.GET("", h.getAllAPIKeys)
+		apiKeys.DELETE("/:id", h.revokeAPIKey)
+		apiKeys.POST("/:id/rotate", h.rotateAPIKey)
+	}
+}
+
+// createAPIKey godoc
+// @Summary      Create API key
+// @Description  Issue an API key for a service caller. The plaintext key is returned only in this response
+// @Tags         admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateAPIKeyRequest  true  "API key data"
+// @Success      201    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateAPIKeyResponse
+// @Failure      400    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/api-keys [post]
+func (h *Handler) createAPIKey(c *gin.Context) {
+	var input handler_dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.APIKey.CreateAPIKey(c.Request.Context(), &service_dto.CreateAPIKeyInput{
+		Name:   input.Name,
+		Scopes: input.Scopes,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidScope) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidScope.Error())
+			return
+		}
+		logger.Error("error occurred while creating api key", err, map[string]interface{}{
+			"name": input.Name,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusCreated, newCreateAPIKeyResponse(res))
+}
+
+// getAllAPIKeys godoc
+// @Summary      Get all API keys
+// @Description  Get the list of all issued API keys, including revoked ones
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllAPIKeysResponse
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/api-keys [get]
+func (h *Handler) getAllAPIKeys(c *gin.Context) {
+	res, err := h.service.APIKey.GetAllAPIKeys(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error("error occurred while getting api keys", err, nil)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	apiKeys := make([]handler_dto.APIKeyResponse, 0, len(res))
+	for _, key := range res {
+		apiKeys = append(apiKeys, newAPIKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, handler_dto.GetAllAPIKeysResponse{APIKeys: apiKeys})
+}
+
+// revokeAPIKey godoc
+// @Summary      Revoke API key
+// @Description  Revoke an API key. Requests made with it are rejected afterwards
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path  string  true  "API key ID"  format(uuid)
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "api key not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/api-keys/{id} [delete]
+func (h *Handler) revokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	if err := h.service.APIKey.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrAPIKeyNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while revoking api key", err, map[string]interface{}{
+			"id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// rotateAPIKey godoc
+// @Summary      Rotate API key
+// @Description  Replace the secret of an API key, keeping its name and scopes. The old key stops working immediately
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateAPIKeyResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "api key not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/api-keys/{id}/rotate [post]
+func (h *Handler) rotateAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.APIKey.RotateAPIKey(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrAPIKeyNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while rotating api key", err, map[string]interface{}{
+			"id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newCreateAPIKeyResponse(res))
+}
+
+func newAPIKeyResponse(key *service_dto.APIKeyOutput) handler_dto.APIKeyResponse {
+	return handler_dto.APIKeyResponse{
+		Id:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RotatedAt: key.RotatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func newCreateAPIKeyResponse(key *service_dto.CreateAPIKeyOutput) handler_dto.CreateAPIKeyResponse {
+	return handler_dto.CreateAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(&key.APIKeyOutput),
+		Key:            key.Key,
+	}
+}
@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+func (h *Handler) initAuditRoutes(api *gin.RouterGroup) {
+	api.GET("/admin/audit", h.getAuditLog)
+}
+
+// getAuditLog godoc
+// @Summary      Get audit log
+// @Description  Get the paginated log of changes of all subscriptions, newest first. Every entry records the caller, the request ID and the state of the subscription before and after the change. Service callers may name the end user they act for in the X-On-Behalf-Of header
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        subscription_id  query     string  false  "Subscription ID"  format(uuid)
+// @Param        user_id          query     string  false  "Owner of the subscription"  format(uuid)
+// @Param        actor            query     string  false  "Caller, e.g. user:<id> or api_key:<id>"
+// @Param        action           query     string  false  "Action"  Enums(create, update, delete, restore)
+// @Param        from             query     string  false  "Changes made at or after, RFC 3339"  format(date-time)
+// @Param        to               query     string  false  "Changes made before, RFC 3339"  format(date-time)
+// @Param        limit            query     int     false  "Limit"   default(20)
+// @Param        offset           query     int     false  "Offset"  default(0)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAuditLogResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/audit [get]
+func (h *Handler) getAuditLog(c *gin.Context) {
+	var input handler_dto.GetAuditLogRequest
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	limit, offset, ok := parsePage(c)
+	if !ok {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Audit.GetAuditLog(c.Request.Context(), service_dto.GetAuditLogInput{
+		Filter: service_dto.AuditFilter{
+			SubscriptionID: input.SubscriptionID,
+			UserID:         input.UserID,
+			Actor:          input.Actor,
+			Action:         input.Action,
+			From:           input.From,
+			To:             input.To,
+		},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAuditAction) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidAuditAction.Error())
+			return
+		}
+		logger.Error("error occurred while getting audit log", err, nil)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newAuditLogResponse(res))
+}
+
+// getSubscriptionHistory godoc
+// @Summary      Get subscription history
+// @Description  Get the paginated audit log of the subscription, newest first: who changed it, when, in which request and how. Users see the changes made while they owned the subscription, including after its deletion
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id      path      string  true   "Subscription ID"  format(uuid)
+// @Param        limit   query     int     false  "Limit"   default(20)
+// @Param        offset  query     int     false  "Offset"  default(0)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAuditLogResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/history [get]
+func (h *Handler) getSubscriptionHistory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	limit, offset, ok := parsePage(c)
+	if !ok {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Subscription.GetSubscriptionHistory(c.Request.Context(), id, service_dto.GetSubscriptionHistoryInput{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while getting subscription history", err, map[string]interface{}{
+			"subscription_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newAuditLogResponse(res))
+}
+
+// parsePage reads the limit and offset query parameters.
+func parsePage(c *gin.Context) (int, int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		return 0, 0, false
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+func newAuditLogResponse(res *service_dto.GetAuditLogOutput) handler_dto.GetAuditLogResponse {
+	entries := make([]handler_dto.AuditEntryResponse, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		entries = append(entries, handler_dto.AuditEntryResponse{
+			Id:             e.ID,
+			SubscriptionID: e.SubscriptionID,
+			UserID:         e.UserID,
+			Action:         e.Action,
+			Actor:          e.Actor,
+			OnBehalfOf:     e.OnBehalfOf,
+			RequestID:      e.RequestID,
+			Before:         e.Before,
+			After:          e.After,
+			Changes:        e.Changes,
+			OccurredAt:     e.OccurredAt,
+		})
+	}
+	return handler_dto.GetAuditLogResponse{
+		Total:   res.Total,
+		Entries: entries,
+	}
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/ical"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// initCalendarRoutes registers the calendar feed, which is authenticated by
+// its token instead of the Authorization header.
+func (h *Handler) initCalendarRoutes(api *gin.RouterGroup) {
+	api.GET("/users/:user_id/renewals.ics", h.getRenewalsCalendar)
+}
+
+// renewalRules maps billing periods to iCalendar recurrence rules.
+var renewalRules = map[string]string{
+	string(domain.BillingPeriodWeekly):    "FREQ=WEEKLY",
+	string(domain.BillingPeriodMonthly):   "FREQ=MONTHLY",
+	string(domain.BillingPeriodQuarterly): "FREQ=MONTHLY;INTERVAL=3",
+	string(domain.BillingPeriodYearly):    "FREQ=YEARLY",
+}
+
+// getRenewalsCalendar godoc
+// @Summary      Get renewals calendar
+// @Description  Get an iCalendar feed with a recurring all-day event for the renewals of every subscription the user has active now. Renewals within the trial or a finished pause are excluded. The feed is authenticated by the token issued for it, so calendar apps can subscribe to its URL
+// @Tags         users
+// @Produce      text/calendar
+// @Param        user_id  path   string  true  "User ID"  format(uuid)
+// @Param        token    query  string  true  "Calendar feed token"
+// @Success      200  {file}    file
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid calendar token"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/renewals.ics [get]
+func (h *Handler) getRenewalsCalendar(c *gin.Context) {
+	userID := c.Param("user_id")
+	token := c.Query("token")
+	if _, err := uuid.Parse(userID); err != nil || token == "" {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Calendar.GetRenewals(c.Request.Context(), userID, token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCalendarToken) {
+			newResponse(c, http.StatusUnauthorized, service.ErrInvalidCalendarToken.Error())
+			return
+		}
+		logger.Error("error occurred while getting renewals calendar", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	var body bytes.Buffer
+	if err := ical.Encode(&body, newRenewalsCalendar(res, time.Now())); err != nil {
+		logger.Error("error occurred while encoding renewals calendar", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="renewals.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body.Bytes())
+}
+
+func newRenewalsCalendar(renewals []*service_dto.RenewalOutput, now time.Time) *ical.Calendar {
+	events := make([]ical.Event, 0, len(renewals))
+	for _, r := range renewals {
+		events = append(events, ical.Event{
+			UID:         r.ID + "@subscription-aggregator",
+			Stamp:       now,
+			Date:        r.StartDate,
+			Summary:     r.ServiceName + " renewal",
+			Description: fmt.Sprintf("%s renews for %d %s (%s)", r.ServiceName, r.Price, r.Currency, r.BillingPeriod),
+			RRule:       renewalRules[r.BillingPeriod],
+			Until:       r.Until,
+			ExDates:     r.SkippedDates,
+		})
+	}
+	return &ical.Calendar{
+		ProdID: "-//subscription-aggregator//renewals//EN",
+		Name:   "Subscription renewals",
+		Events: events,
+	}
+}
+
+// createCalendarToken godoc
+// @Summary      Create calendar token
+// @Description  Issue the token of the renewals calendar feed of a user, invalidating the previous one. The token is returned only once, together with the feed URL
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"  format(uuid)
+// @Success      201  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CalendarTokenResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/calendar-token [post]
+func (h *Handler) createCalendarToken(c *gin.Context) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	token, err := h.service.Calendar.CreateCalendarToken(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error("error occurred while creating calendar token", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feed := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     "/api/v1/users/" + userID + "/renewals.ics",
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	c.JSON(http.StatusCreated, handler_dto.CalendarTokenResponse{
+		Token: token,
+		URL:   feed.String(),
+	})
+}
+
+// revokeCalendarToken godoc
+// @Summary      Revoke calendar token
+// @Description  Revoke the token of the renewals calendar feed of a user, disabling the feed URL
+// @Tags         users
+// @Security     BearerAuth
+// @Param        user_id  path  string  true  "User ID"  format(uuid)
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "calendar token not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/calendar-token [delete]
+func (h *Handler) revokeCalendarToken(c *gin.Context) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if err := h.service.Calendar.RevokeCalendarToken(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrCalendarTokenNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrCalendarTokenNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while revoking calendar token", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// subscriptionETag renders a strong entity tag of a subscription from its
+// version and a hash of its representation. The version alone does not
+// identify the representation: the current price and the status are derived
+// from the date and change without a new version.
+func subscriptionETag(version int64, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// ifMatchVersion reads the version expected by the If-Match header from the
+// version part of the tag, so that a tag still matches once the derived
+// fields of the subscription change. The version is nil when the header is
+// absent or "*". It returns false when the header cannot match any
+// subscription: weak tags never match, and a list of several tags is not
+// supported.
+func ifMatchVersion(c *gin.Context) (*int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	tags := splitETags(header)
+	if len(tags) != 1 {
+		return nil, false
+	}
+	tag := tags[0]
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, false
+	}
+	versionPart, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseInt(versionPart, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &version, true
+}
+
+// notModified reports whether the If-None-Match header matches the entity
+// tag, using the weak comparison.
+func notModified(c *gin.Context, etag string) bool {
+	header := strings.TrimSpace(c.GetHeader("If-None-Match"))
+	if header == "*" {
+		return true
+	}
+	for _, tag := range splitETags(header) {
+		if strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func splitETags(header string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
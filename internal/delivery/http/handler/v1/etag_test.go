@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func etagContext(header, value string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		c.Request.Header.Set(header, value)
+	}
+	return c
+}
+
+func TestSubscriptionETagFollowsRepresentation(t *testing.T) {
+	active := subscriptionETag(3, []byte(`{"price":400,"status":"active"}`))
+	expired := subscriptionETag(3, []byte(`{"price":400,"status":"expired"}`))
+	if active == expired {
+		t.Errorf("ETag %s unchanged when the derived status changes", active)
+	}
+	if again := subscriptionETag(3, []byte(`{"price":400,"status":"active"}`)); again != active {
+		t.Errorf("ETag = %s for the same representation, want %s", again, active)
+	}
+	if !notModified(etagContext("If-None-Match", active), active) {
+		t.Error("If-None-Match with the current ETag did not match")
+	}
+	if notModified(etagContext("If-None-Match", active), expired) {
+		t.Error("If-None-Match with a stale ETag matched")
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		ok      bool
+	}{
+		{"absent", "", 0, true},
+		{"any", "*", 0, true},
+		{"from GET", subscriptionETag(7, []byte(`{}`)), 7, true},
+		{"version only", `"7"`, 7, true},
+		{"weak", `W/"7"`, 0, false},
+		{"several", `"7", "8"`, 0, false},
+		{"malformed", `"v7"`, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := ifMatchVersion(etagContext("If-Match", tt.header))
+			if ok != tt.ok {
+				t.Fatalf("ifMatchVersion() ok = %v, want %v", ok, tt.ok)
+			}
+			if tt.version == 0 && version != nil {
+				t.Errorf("ifMatchVersion() = %d, want nil", *version)
+			}
+			if tt.version != 0 && (version == nil || *version != tt.version) {
+				t.Errorf("ifMatchVersion() = %v, want %d", version, tt.version)
+			}
+		})
+	}
+}
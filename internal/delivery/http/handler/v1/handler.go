@@ -18,5 +18,18 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 	v1 := api.Group("/v1")
 	{
 		h.initSubscriptionsRoutes(v1)
+		h.initUsersRoutes(v1)
+		h.initServicesRoutes(v1)
+		h.initAdminRoutes(v1)
+		h.initWebhooksRoutes(v1)
+		h.initAuditRoutes(v1)
+	}
+}
+
+// InitPublic registers the routes that do not require authentication.
+func (h *Handler) InitPublic(api *gin.RouterGroup) {
+	v1 := api.Group("/v1")
+	{
+		h.initCalendarRoutes(v1)
 	}
 }
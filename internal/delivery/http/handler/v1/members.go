@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// getSubscriptionMembers godoc
+// @Summary      Get subscription members
+// @Description  Get the users the subscription is shared with and their shares
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetMembersResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/members [get]
+func (h *Handler) getSubscriptionMembers(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.GetSubscriptionMembers(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while getting subscription members", err, map[string]interface{}{
+			"subscription_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, handler_dto.GetMembersResponse{
+		Members: newMemberResponses(res),
+	})
+}
+
+// addSubscriptionMember godoc
+// @Summary      Add subscription member
+// @Description  Share the subscription with a user. Exactly one of weight and fixed_amount must be set: fixed amounts are taken from every charge first and the rest is split by weight, the payer having a weight of 1 unless added as a member. Adding an existing member replaces their share
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       json
+// @Param        id      path  string  true  "Subscription ID"  format(uuid)
+// @Param        member  body  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.AddMemberRequest  true  "Member share"
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/members [post]
+func (h *Handler) addSubscriptionMember(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var input handler_dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	err := h.service.Subscription.AddSubscriptionMember(c.Request.Context(), id, &service_dto.AddMemberInput{
+		UserID:      input.UserID,
+		Weight:      input.Weight,
+		FixedAmount: input.FixedAmount,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidMember) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidMember.Error())
+			return
+		}
+		logger.Error("error occurred while adding subscription member", err, map[string]interface{}{
+			"subscription_id": id,
+			"user_id":         input.UserID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// removeSubscriptionMember godoc
+// @Summary      Remove subscription member
+// @Description  Stop sharing the subscription with a user
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Subscription ID"  format(uuid)
+// @Param        user_id  path  string  true  "Member user ID"   format(uuid)
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription or member not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/members/{user_id} [delete]
+func (h *Handler) removeSubscriptionMember(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	if err := h.service.Subscription.RemoveSubscriptionMember(c.Request.Context(), id, userID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrMemberNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrMemberNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while removing subscription member", err, map[string]interface{}{
+			"subscription_id": id,
+			"user_id":         userID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func newMemberResponses(members []service_dto.MemberOutput) []handler_dto.MemberResponse {
+	responses := make([]handler_dto.MemberResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, handler_dto.MemberResponse{
+			UserID:      m.UserID,
+			Weight:      m.Weight,
+			FixedAmount: m.FixedAmount,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+)
+
+// requireScope rejects API key callers whose key lacks scope.
+func requireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			newResponse(c, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if !identity.HasScope(scope) {
+			newResponse(c, http.StatusForbidden, "forbidden")
+			return
+		}
+		c.Next()
+	}
+}
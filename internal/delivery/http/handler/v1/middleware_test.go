@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+)
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		status   int
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized},
+		{name: "user", identity: &auth.Identity{Subject: "user"}, status: http.StatusOK},
+		{
+			name:     "api key with the scope",
+			identity: &auth.Identity{APIKeyID: "key", Scopes: []string{auth.ScopeSubscriptionsRead, auth.ScopeReportsRead}},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "api key without the scope",
+			identity: &auth.Identity{APIKeyID: "key", Scopes: []string{auth.ScopeSubscriptionsRead}},
+			status:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(w)
+			r.GET("/report", requireScope(auth.ScopeReportsRead), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			ctx := context.Background()
+			if tt.identity != nil {
+				ctx = auth.WithIdentity(ctx, tt.identity)
+			}
+			c.Request = httptest.NewRequest(http.MethodGet, "/report", nil).WithContext(ctx)
+			r.HandleContext(c)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// getSpendReport godoc
+// @Summary      Get spend report
+// @Description  Calculate spend for a given period grouped by service name, user and/or billing month. Subscriptions are charged the same way as for the total price: when filtered or grouped by user, every user is charged their share of the subscriptions they pay for or are members of
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        group_by      query  string  false  "Comma-separated grouping: service_name, user_id, month"
+// @Param        user_id       query  string  false  "User ID"       format(uuid)
+// @Param        service_name  query  string  false  "Service name"
+// @Param        start_date    query  string  true   "Period start (MM-YYYY)"
+// @Param        end_date      query  string  true   "Period end (MM-YYYY)"
+// @Param        currency      query  string  false  "ISO 4217 currency of the report"
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetReportResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/report [get]
+func (h *Handler) getSpendReport(c *gin.Context) {
+	var input handler_dto.GetReportRequest
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if input.EndDate.Before(input.StartDate.Time) {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	groupBy := make([]string, 0)
+	for _, field := range strings.Split(input.GroupBy, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			groupBy = append(groupBy, field)
+		}
+	}
+
+	res, err := h.service.Subscription.GetSpendReport(c.Request.Context(), &service_dto.GetReportInput{
+		GroupBy:     groupBy,
+		UserID:      input.UserID,
+		ServiceName: input.ServiceName,
+		StartDate:   input.StartDate.Time,
+		EndDate:     input.EndDate.Time,
+		Currency:    input.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidGroupBy) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidGroupBy.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			newResponse(c, http.StatusBadRequest, service.ErrUnsupportedCurrency.Error())
+			return
+		}
+		logger.Error("error occurred while getting spend report", err, map[string]interface{}{
+			"group_by":     input.GroupBy,
+			"user_id":      input.UserID,
+			"service_name": input.ServiceName,
+			"start_date":   input.StartDate,
+			"end_date":     input.EndDate,
+			"currency":     input.Currency,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	rows := make([]handler_dto.ReportRowResponse, 0, len(res.Rows))
+	for _, i := range res.Rows {
+		var month *handler_dto.MonthYear
+		if i.Month != nil {
+			month = &handler_dto.MonthYear{Time: *i.Month}
+		}
+		rows = append(rows, handler_dto.ReportRowResponse{
+			ServiceName: i.ServiceName,
+			UserID:      i.UserID,
+			Month:       month,
+			Months:      i.Months,
+			Charges:     i.Charges,
+			TotalPrice:  i.TotalPrice,
+		})
+	}
+	c.JSON(http.StatusOK, handler_dto.GetReportResponse{
+		Currency:   res.Currency,
+		GroupBy:    res.GroupBy,
+		TotalPrice: res.TotalPrice,
+		Rows:       rows,
+	})
+}
@@ -0,0 +1,249 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+func (h *Handler) initServicesRoutes(api *gin.RouterGroup) {
+	read := requireScope(auth.ScopeSubscriptionsRead)
+	services := api.Group("/services")
+	{
+		services.POST("", h.createService)
+		services.GET("", read, h.getAllServices)
+		services.GET("/:id", read, h.getServiceById)
+		services.PUT("/:id", h.updateService)
+		services.DELETE("/:id", h.deleteService)
+	}
+}
+
+// createService godoc
+// @Summary      Create service
+// @Description  Add a service to the catalog. Subscriptions whose service name matches the name or one of the aliases, ignoring case, are attached to it
+// @Tags         services
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        service  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateServiceRequest  true  "Service data"
+// @Success      201      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ServiceResponse
+// @Failure      400      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      409      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "service name is already taken"
+// @Failure      500      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/services [post]
+func (h *Handler) createService(c *gin.Context) {
+	var input handler_dto.CreateServiceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Catalog.CreateService(c.Request.Context(), &service_dto.CreateServiceInput{
+		Name:         input.Name,
+		Aliases:      input.Aliases,
+		Category:     input.Category,
+		DefaultPrice: input.DefaultPrice,
+		LogoURL:      input.LogoURL,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidService) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidService.Error())
+			return
+		}
+		if errors.Is(err, service.ErrServiceConflict) {
+			newResponse(c, http.StatusConflict, service.ErrServiceConflict.Error())
+			return
+		}
+		logger.Error("error occurred while creating a service", err, map[string]interface{}{
+			"name": input.Name,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusCreated, newServiceResponse(res))
+}
+
+// getAllServices godoc
+// @Summary      Get all services
+// @Description  Get the service catalog ordered by name
+// @Tags         services
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllServicesResponse
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/services [get]
+func (h *Handler) getAllServices(c *gin.Context) {
+	res, err := h.service.Catalog.GetAllServices(c.Request.Context())
+	if err != nil {
+		logger.Error("error occurred while getting services", err, nil)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	services := make([]handler_dto.ServiceResponse, 0, len(res))
+	for _, s := range res {
+		services = append(services, newServiceResponse(s))
+	}
+	c.JSON(http.StatusOK, handler_dto.GetAllServicesResponse{Services: services})
+}
+
+// getServiceById godoc
+// @Summary      Get service by ID
+// @Description  Get catalog entry details by ID
+// @Tags         services
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Service ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ServiceResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "service not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/services/{id} [get]
+func (h *Handler) getServiceById(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Catalog.GetServiceById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrServiceNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrServiceNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while getting service by id", err, map[string]interface{}{
+			"service_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newServiceResponse(res))
+}
+
+// updateService godoc
+// @Summary      Update service
+// @Description  Update a catalog entry by ID. Aliases, when given, replace the current ones
+// @Tags         services
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Service ID"  format(uuid)
+// @Param        service  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.UpdateServiceRequest  true  "Updated data"
+// @Success      200      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ServiceResponse
+// @Failure      400      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "service not found"
+// @Failure      409      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "service name is already taken"
+// @Failure      500      {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/services/{id} [put]
+func (h *Handler) updateService(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var input handler_dto.UpdateServiceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Catalog.UpdateService(c.Request.Context(), id, &service_dto.UpdateServiceInput{
+		Name:         input.Name,
+		Aliases:      input.Aliases,
+		Category:     input.Category,
+		DefaultPrice: input.DefaultPrice,
+		LogoURL:      input.LogoURL,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrServiceNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrServiceNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidService) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidService.Error())
+			return
+		}
+		if errors.Is(err, service.ErrServiceConflict) {
+			newResponse(c, http.StatusConflict, service.ErrServiceConflict.Error())
+			return
+		}
+		logger.Error("error occurred while updating service", err, map[string]interface{}{
+			"service_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newServiceResponse(res))
+}
+
+// deleteService godoc
+// @Summary      Delete service
+// @Description  Remove a service from the catalog. Subscriptions attached to it keep their service name
+// @Tags         services
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Service ID"  format(uuid)
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "service not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/services/{id} [delete]
+func (h *Handler) deleteService(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if err := h.service.Catalog.DeleteService(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrServiceNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrServiceNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while deleting service", err, map[string]interface{}{
+			"service_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func newServiceResponse(s *service_dto.ServiceOutput) handler_dto.ServiceResponse {
+	return handler_dto.ServiceResponse{
+		Id:           s.ID,
+		Name:         s.Name,
+		Aliases:      s.Aliases,
+		Category:     s.Category,
+		DefaultPrice: s.DefaultPrice,
+		LogoURL:      s.LogoURL,
+		CreatedAt:    s.CreatedAt,
+	}
+}
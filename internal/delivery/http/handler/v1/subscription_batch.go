@@ -0,0 +1,300 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// maxBatchSize limits the number of items of a batch request.
+const maxBatchSize = 100
+
+// createSubscriptions godoc
+// @Summary      Create subscriptions in batch
+// @Description  Create up to 100 subscriptions in a single transaction. With atomic=true (default) nothing is created unless every item succeeds and the response is 422 otherwise; with atomic=false the valid items are created. Results are listed in the order of the request
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic         query     bool  false  "Roll back the whole batch when an item fails"  default(true)
+// @Param        subscriptions  body      []github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateSubscriptionRequest  true  "Subscriptions data"
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      422  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse  "batch rolled back"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/batch [post]
+func (h *Handler) createSubscriptions(c *gin.Context) {
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "true"))
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	requests, invalid, ok := bindBatch[handler_dto.CreateSubscriptionRequest](c)
+	if !ok {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	inputs := make([]*service_dto.CreateSubscriptionInput, 0, len(requests))
+	for i, input := range requests {
+		if invalid[i] {
+			continue
+		}
+		inputs = append(inputs, newCreateSubscriptionInput(&input))
+	}
+	if atomic && len(inputs) < len(requests) {
+		newBatchResponse(c, invalid, rolledBackBatch(make([]string, len(inputs))))
+		return
+	}
+
+	res, err := h.service.Subscription.CreateSubscriptions(c.Request.Context(), inputs, atomic)
+	if err != nil {
+		logger.Error("error occurred while creating subscriptions in batch", err, map[string]interface{}{
+			"count":  len(inputs),
+			"atomic": atomic,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	newBatchResponse(c, invalid, res)
+}
+
+// updateSubscriptions godoc
+// @Summary      Update subscriptions in batch
+// @Description  Update up to 100 subscriptions, each identified by subscription_id, in a single transaction. An item with a version fails with "subscription version mismatch" unless the subscription still has that version. With atomic=true (default) nothing is updated unless every item succeeds and the response is 422 otherwise; with atomic=false the failed items are skipped. Results are listed in the order of the request. Repeated IDs are rejected
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic         query     bool  false  "Roll back the whole batch when an item fails"  default(true)
+// @Param        subscriptions  body      []github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchUpdateSubscriptionRequest  true  "Updated data"
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      422  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse  "batch rolled back"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/batch [put]
+func (h *Handler) updateSubscriptions(c *gin.Context) {
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "true"))
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	requests, invalid, ok := bindBatch[handler_dto.BatchUpdateSubscriptionRequest](c)
+	if !ok {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	inputs := make([]*service_dto.BatchUpdateInput, 0, len(requests))
+	ids := make([]string, 0, len(requests))
+	seen := make(map[uuid.UUID]bool, len(requests))
+	for i, input := range requests {
+		if invalid[i] {
+			continue
+		}
+		// Repeated IDs would make the result of an item depend on the
+		// updates before it, and the versions of later ones stale.
+		id := uuid.MustParse(input.Id)
+		if seen[id] {
+			newResponse(c, http.StatusBadRequest, "invalid data")
+			return
+		}
+		seen[id] = true
+		var startDate, endDate, priceEffectiveFrom *time.Time
+		if input.StartDate != nil {
+			startDate = &input.StartDate.Time
+		}
+		if input.EndDate != nil {
+			endDate = &input.EndDate.Time
+		}
+		if input.PriceEffectiveFrom != nil {
+			priceEffectiveFrom = &input.PriceEffectiveFrom.Time
+		}
+		inputs = append(inputs, &service_dto.BatchUpdateInput{
+			ID: input.Id,
+			Update: service_dto.UpdateSubscriptionInput{
+				ServiceName:        input.ServiceName,
+				Price:              input.Price,
+				Currency:           input.Currency,
+				BillingPeriod:      input.BillingPeriod,
+				UserID:             input.UserID,
+				StartDate:          startDate,
+				EndDate:            endDate,
+				TrialEndDate:       input.TrialEndDate.TimePtr(),
+				PriceEffectiveFrom: priceEffectiveFrom,
+				Tags:               input.Tags,
+				Version:            input.Version,
+			},
+		})
+		ids = append(ids, input.Id)
+	}
+	if atomic && len(inputs) < len(requests) {
+		newBatchResponse(c, invalid, rolledBackBatch(ids))
+		return
+	}
+
+	res, err := h.service.Subscription.UpdateSubscriptions(c.Request.Context(), inputs, atomic)
+	if err != nil {
+		logger.Error("error occurred while updating subscriptions in batch", err, map[string]interface{}{
+			"subscription_ids": ids,
+			"atomic":           atomic,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	newBatchResponse(c, invalid, res)
+}
+
+// deleteSubscriptions godoc
+// @Summary      Delete subscriptions in batch
+// @Description  Delete up to 100 subscriptions in a single transaction. With atomic=true (default) nothing is deleted unless every item succeeds and the response is 422 otherwise; with atomic=false the failed items are skipped. Results are listed in the order of the request. Repeated IDs are rejected. Versions are not checked: use DELETE /subscriptions/{id} with If-Match to delete conditionally
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic         query     bool  false  "Roll back the whole batch when an item fails"  default(true)
+// @Param        subscriptions  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchDeleteSubscriptionsRequest  true  "Subscription IDs"
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      422  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.BatchResponse  "batch rolled back"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/batch [delete]
+func (h *Handler) deleteSubscriptions(c *gin.Context) {
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "true"))
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var input handler_dto.BatchDeleteSubscriptionsRequest
+	if err := c.ShouldBindJSON(&input); err != nil || len(input.Ids) > maxBatchSize {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	invalid := make([]bool, len(input.Ids))
+	ids := make([]string, 0, len(input.Ids))
+	seen := make(map[uuid.UUID]bool, len(input.Ids))
+	for i, id := range input.Ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			invalid[i] = true
+			continue
+		}
+		// A repeated ID would fail as not found once the first occurrence
+		// is deleted and roll back an atomic batch.
+		if seen[parsed] {
+			newResponse(c, http.StatusBadRequest, "invalid data")
+			return
+		}
+		seen[parsed] = true
+		ids = append(ids, id)
+	}
+	if atomic && len(ids) < len(input.Ids) {
+		newBatchResponse(c, invalid, rolledBackBatch(ids))
+		return
+	}
+
+	res, err := h.service.Subscription.DeleteSubscriptions(c.Request.Context(), ids, atomic)
+	if err != nil {
+		logger.Error("error occurred while deleting subscriptions in batch", err, map[string]interface{}{
+			"subscription_ids": ids,
+			"atomic":           atomic,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	newBatchResponse(c, invalid, res)
+}
+
+// bindBatch decodes a JSON array of up to maxBatchSize items and validates
+// every item on its own, so that an invalid item fails only itself in a
+// non-atomic batch. It reports false when the body is not such an array.
+func bindBatch[T any](c *gin.Context) ([]T, []bool, bool) {
+	var raw []json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil || len(raw) == 0 || len(raw) > maxBatchSize {
+		return nil, nil, false
+	}
+	items, invalid := decodeBatch[T](raw)
+	return items, invalid, true
+}
+
+// decodeBatch decodes and validates every item, reporting which of them are
+// invalid.
+func decodeBatch[T any](raw []json.RawMessage) ([]T, []bool) {
+	items := make([]T, len(raw))
+	invalid := make([]bool, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &items[i]); err != nil {
+			invalid[i] = true
+			continue
+		}
+		if err := binding.Validator.ValidateStruct(&items[i]); err != nil {
+			invalid[i] = true
+		}
+	}
+	return items, invalid
+}
+
+// rolledBackBatch is the result of an atomic batch rejected before reaching
+// the service because some of its items are invalid.
+func rolledBackBatch(ids []string) *service_dto.BatchOutput {
+	items := make([]service_dto.BatchItemOutput, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, service_dto.BatchItemOutput{
+			ID:  id,
+			Err: service.ErrBatchRolledBack,
+		})
+	}
+	return &service_dto.BatchOutput{Items: items}
+}
+
+// newBatchResponse merges the results of the valid items with the invalid
+// ones in the order of the request.
+func newBatchResponse(c *gin.Context, invalid []bool, res *service_dto.BatchOutput) {
+	results := make([]handler_dto.BatchItemResponse, 0, len(invalid))
+	next := 0
+	for _, bad := range invalid {
+		if bad {
+			results = append(results, handler_dto.BatchItemResponse{Error: "invalid data"})
+			continue
+		}
+		item := res.Items[next]
+		next++
+		result := handler_dto.BatchItemResponse{
+			Id:      item.ID,
+			Success: item.Err == nil,
+		}
+		if item.Err != nil {
+			result.Error = item.Err.Error()
+		}
+		if errors.Is(item.Err, service.ErrBatchItemFailed) {
+			logger.Error("error occurred while saving a batch item", item.Err, map[string]interface{}{
+				"subscription_id": item.ID,
+			})
+			result.Error = service.ErrBatchItemFailed.Error()
+		}
+		results = append(results, result)
+	}
+	status := http.StatusOK
+	if !res.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, handler_dto.BatchResponse{
+		Committed: res.Committed,
+		Results:   results,
+	})
+}
@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+	"github.com/scmbr/subscription-aggregator/pkg/xlsx"
+)
+
+// exportColumns are the columns of CSV and XLSX exports, named after the
+// JSON fields of GetSubscriptionResponse.
+var exportColumns = []interface{}{
+	"subscription_id",
+	"service_name",
+	"service_id",
+	"price",
+	"currency",
+	"billing_period",
+	"monthly_price",
+	"user_id",
+	"start_date",
+	"end_date",
+	"trial_end_date",
+	"status",
+	"cancelled_at",
+	"tags",
+	"deleted_at",
+}
+
+// exportRow returns the cells of a subscription in the order of
+// exportColumns, with nil for missing values.
+func exportRow(res *handler_dto.GetSubscriptionResponse) []interface{} {
+	row := []interface{}{
+		res.Id,
+		res.ServiceName,
+		nil,
+		res.Price,
+		res.Currency,
+		res.BillingPeriod,
+		res.MonthlyPrice,
+		res.UserID,
+		res.StartDate.String(),
+		nil,
+		nil,
+		res.Status,
+		nil,
+		strings.Join(res.Tags, ";"),
+		nil,
+	}
+	if res.ServiceID != nil {
+		row[2] = *res.ServiceID
+	}
+	if res.EndDate != nil {
+		row[9] = res.EndDate.String()
+	}
+	if res.TrialEndDate != nil {
+		row[10] = res.TrialEndDate.String()
+	}
+	if res.CancelledAt != nil {
+		row[12] = res.CancelledAt.UTC().Format(time.RFC3339)
+	}
+	if res.DeletedAt != nil {
+		row[14] = res.DeletedAt.UTC().Format(time.RFC3339)
+	}
+	return row
+}
+
+// subscriptionExporter writes the subscriptions of an export one at a time.
+type subscriptionExporter interface {
+	Write(res *handler_dto.GetSubscriptionResponse) error
+	Close() error
+}
+
+// exportFormats maps the supported formats to their content types.
+var exportFormats = map[string]string{
+	"csv":   "text/csv; charset=utf-8",
+	"xlsx":  xlsx.ContentType,
+	"jsonl": "application/jsonl",
+}
+
+func newSubscriptionExporter(format string, w io.Writer) (subscriptionExporter, error) {
+	switch format {
+	case "csv":
+		exporter := &csvExporter{w: csv.NewWriter(w)}
+		return exporter, exporter.writeRow(exportColumns)
+	case "xlsx":
+		sheet, err := xlsx.NewWriter(w, "Subscriptions")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExporter{w: sheet}, sheet.WriteRow(exportColumns...)
+	case "jsonl":
+		return &jsonlExporter{enc: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) Write(res *handler_dto.GetSubscriptionResponse) error {
+	return e.writeRow(exportRow(res))
+}
+
+func (e *csvExporter) writeRow(cells []interface{}) error {
+	record := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		if cell == nil {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, fmt.Sprint(cell))
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type xlsxExporter struct {
+	w *xlsx.Writer
+}
+
+func (e *xlsxExporter) Write(res *handler_dto.GetSubscriptionResponse) error {
+	return e.w.WriteRow(exportRow(res)...)
+}
+
+func (e *xlsxExporter) Close() error {
+	return e.w.Close()
+}
+
+type jsonlExporter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExporter) Write(res *handler_dto.GetSubscriptionResponse) error {
+	return e.enc.Encode(res)
+}
+
+func (e *jsonlExporter) Close() error {
+	return nil
+}
+
+// exportSubscriptions godoc
+// @Summary      Export subscriptions
+// @Description  Download all subscriptions matching the filters of the list endpoint as a CSV, XLSX or JSON Lines file, ordered by creation time. Months are rendered as MM-YYYY and trial end dates as YYYY-MM-DD; tags are separated by semicolons in CSV and XLSX files. The file is streamed as subscriptions are read
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/jsonl
+// @Param        format               query     string  true   "File format"  Enums(csv, xlsx, jsonl)
+// @Param        user_id              query     string  false  "User ID"  format(uuid)
+// @Param        service_name         query     string  false  "Exact service name"
+// @Param        service_name_prefix  query     string  false  "Case-insensitive service name prefix"
+// @Param        min_price            query     int     false  "Minimum current price"
+// @Param        max_price            query     int     false  "Maximum current price"
+// @Param        active_at            query     string  false  "Active in month (MM-YYYY)"
+// @Param        start_date_from      query     string  false  "Start date from (MM-YYYY)"
+// @Param        start_date_to        query     string  false  "Start date to (MM-YYYY)"
+// @Param        end_date_from        query     string  false  "End date from (MM-YYYY)"
+// @Param        end_date_to          query     string  false  "End date to (MM-YYYY)"
+// @Param        no_end_date          query     bool    false  "Only subscriptions without (true) or with (false) an end date"
+// @Param        trial_ending_within  query     int     false  "Only subscriptions whose trial ends within the given number of days from today"
+// @Param        tag                  query     string  false  "Only subscriptions with the tag"
+// @Param        include_deleted      query     bool    false  "Include deleted subscriptions that are not purged yet; admins only"
+// @Success      200  {file}    file
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/export [get]
+func (h *Handler) exportSubscriptions(c *gin.Context) {
+	format := c.Query("format")
+	contentType, ok := exportFormats[format]
+	if !ok {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var input handler_dto.GetAllSubscriptionsRequest
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	// The response starts with the first subscription, so that errors
+	// occurring before it can still be reported as JSON.
+	var exporter subscriptionExporter
+	start := func() error {
+		filename := fmt.Sprintf("subscriptions-%s.%s", time.Now().UTC().Format(time.DateOnly), format)
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Status(http.StatusOK)
+		var err error
+		exporter, err = newSubscriptionExporter(format, c.Writer)
+		return err
+	}
+	err := h.service.Subscription.ExportSubscriptions(c.Request.Context(), subscriptionFilterFromRequest(&input), func(res *service_dto.GetSubscriptionOutput) error {
+		if exporter == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		response := newGetSubscriptionResponse(res)
+		return exporter.Write(&response)
+	})
+	if err == nil && exporter == nil {
+		err = start()
+	}
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err != nil {
+		if exporter != nil {
+			logger.Error("error occurred while streaming subscriptions export", err, map[string]interface{}{
+				"format": format,
+			})
+			c.Abort()
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error("error occurred while exporting subscriptions", err, map[string]interface{}{
+			"format": format,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+}
@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+const (
+	maxImportSize = 1 << 20
+	maxImportRows = 1000
+)
+
+// importColumns are the CSV columns, named after the JSON fields of
+// CreateSubscriptionRequest.
+var importColumns = map[string]bool{
+	"service_name":   true,
+	"price":          true,
+	"currency":       true,
+	"billing_period": true,
+	"user_id":        true,
+	"start_date":     true,
+	"end_date":       true,
+	"trial_end_date": true,
+	"tags":           true,
+}
+
+// importSubscriptions godoc
+// @Summary      Import subscriptions
+// @Description  Import up to 1000 subscriptions from a CSV or JSON Lines file. CSV files start with a header naming the columns after the fields of the create request (service_name, price, currency, billing_period, user_id, start_date, end_date, trial_end_date, tags), dates are in the MM-YYYY format, trial_end_date in YYYY-MM-DD and tags are separated by semicolons. JSON Lines files hold one create request per line. The subscriptions are created in a single transaction only when every row is valid; otherwise, or with dry_run=true, nothing is created and the response reports every row
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "CSV or JSON Lines file"
+// @Param        format   formData  string  false  "File format, by the file extension by default"  Enums(csv, jsonl)
+// @Param        dry_run  query     bool    false  "Only validate the file"  default(false)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ImportResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      422  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ImportResponse  "invalid rows"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/import [post]
+func (h *Handler) importSubscriptions(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil || header.Size > maxImportSize {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	format := c.PostForm("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	file, err := header.Open()
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	defer file.Close()
+
+	var raw []json.RawMessage
+	var lines []int
+	switch format {
+	case "csv":
+		raw, lines, err = readImportCSV(file)
+	case "jsonl", "ndjson":
+		raw, lines, err = readImportJSONL(file)
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil || len(raw) == 0 || len(raw) > maxImportRows {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	requests, invalid := decodeBatch[handler_dto.CreateSubscriptionRequest](raw)
+	inputs := make([]*service_dto.CreateSubscriptionInput, 0, len(requests))
+	for i, input := range requests {
+		if !invalid[i] {
+			inputs = append(inputs, newCreateSubscriptionInput(&input))
+		}
+	}
+	res, err := h.service.Subscription.ImportSubscriptions(c.Request.Context(), inputs, dryRun || len(inputs) < len(requests))
+	if err != nil {
+		logger.Error("error occurred while importing subscriptions", err, map[string]interface{}{
+			"file":    header.Filename,
+			"rows":    len(raw),
+			"dry_run": dryRun,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	response := handler_dto.ImportResponse{
+		DryRun:    dryRun,
+		Committed: res.Committed,
+		Total:     len(raw),
+		Rows:      make([]handler_dto.ImportRowResponse, 0, len(raw)),
+	}
+	next := 0
+	for i, bad := range invalid {
+		row := handler_dto.ImportRowResponse{Row: lines[i], Valid: true}
+		if bad {
+			row.Valid, row.Error = false, "invalid data"
+		} else {
+			item := res.Items[next]
+			next++
+			row.Id = item.ID
+			if item.Err != nil {
+				row.Valid, row.Error = false, item.Err.Error()
+			}
+		}
+		if !row.Valid {
+			response.Invalid++
+		}
+		response.Rows = append(response.Rows, row)
+	}
+	status := http.StatusOK
+	if !dryRun && !res.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, response)
+}
+
+// readImportCSV converts every CSV record to a JSON create request and
+// returns the line numbers of the records. Malformed records are kept as
+// invalid JSON, so that they fail only their own row.
+func readImportCSV(r io.Reader) ([]json.RawMessage, []int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !importColumns[column] {
+			return nil, nil, fmt.Errorf("unknown column %q", column)
+		}
+		columns[i] = column
+	}
+
+	raw := make([]json.RawMessage, 0)
+	lines := make([]int, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		lines = append(lines, line)
+		if len(record) != len(columns) {
+			raw = append(raw, nil)
+			continue
+		}
+		fields := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+			switch column {
+			case "price":
+				if price, err := strconv.Atoi(value); err == nil {
+					fields[column] = price
+				} else {
+					fields[column] = value
+				}
+			case "tags":
+				tags := strings.Split(value, ";")
+				for j := range tags {
+					tags[j] = strings.TrimSpace(tags[j])
+				}
+				fields[column] = tags
+			default:
+				fields[column] = value
+			}
+		}
+		row, err := json.Marshal(fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		raw = append(raw, row)
+	}
+	return raw, lines, nil
+}
+
+// readImportJSONL returns the non-empty lines and their line numbers.
+func readImportJSONL(r io.Reader) ([]json.RawMessage, []int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportSize)
+	raw := make([]json.RawMessage, 0)
+	lines := make([]int, 0)
+	for line := 1; scanner.Scan(); line++ {
+		row := bytes.TrimSpace(scanner.Bytes())
+		if len(row) == 0 {
+			continue
+		}
+		raw = append(raw, json.RawMessage(bytes.Clone(row)))
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return raw, lines, nil
+}
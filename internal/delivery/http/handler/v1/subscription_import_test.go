@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+)
+
+const importUserID = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+
+func TestReadImportCSV(t *testing.T) {
+	file := "\ufeffService_Name, Price ,user_id,start_date,end_date,tags\n" +
+		"Yandex Plus,400," + importUserID + ",07-2025,,Music; Family\n" +
+		"\"Netflix,\nPremium\",999," + importUserID + ",01-2025,12-2025,\n" +
+		"Spotify,free," + importUserID + ",01-2025,,\n" +
+		"Kinopoisk,300\n" +
+		"Okko,,," + importUserID + ",01-2025,\n"
+	raw, lines, err := readImportCSV(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("readImportCSV() error = %v", err)
+	}
+	if want := []int{2, 3, 5, 6, 7}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %v, want %v", lines, want)
+	}
+	requests, invalid := decodeBatch[handler_dto.CreateSubscriptionRequest](raw)
+	if want := []bool{false, false, true, true, true}; !reflect.DeepEqual(invalid, want) {
+		t.Fatalf("invalid = %v, want %v", invalid, want)
+	}
+
+	first := requests[0]
+	if first.ServiceName != "Yandex Plus" || first.Price != 400 || first.UserID != importUserID {
+		t.Errorf("first row = %+v", first)
+	}
+	if !first.StartDate.Equal(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)) || first.EndDate != nil {
+		t.Errorf("first row dates = %v, %v, want 07-2025 and none", first.StartDate.Time, first.EndDate)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"Music", "Family"}) {
+		t.Errorf("first row tags = %q", first.Tags)
+	}
+	second := requests[1]
+	if second.ServiceName != "Netflix,\nPremium" || second.EndDate == nil || !second.EndDate.Equal(time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("second row = %+v", second)
+	}
+}
+
+func TestReadImportCSVRejectsUnknownColumns(t *testing.T) {
+	if _, _, err := readImportCSV(strings.NewReader("service_name,cost\nNetflix,999\n")); err == nil {
+		t.Error("readImportCSV() accepted an unknown column")
+	}
+}
+
+func TestReadImportJSONL(t *testing.T) {
+	file := `{"service_name":"Yandex Plus","price":400,"user_id":"` + importUserID + `","start_date":"07-2025"}` + "\n" +
+		"\n" +
+		"  \r\n" +
+		`{"service_name":"` + strings.Repeat("n", 31) + `","price":400,"user_id":"` + importUserID + `","start_date":"07-2025"}` + "\n" +
+		`{"service_name":"Netflix"` + "\n"
+	raw, lines, err := readImportJSONL(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("readImportJSONL() error = %v", err)
+	}
+	if want := []int{1, 4, 5}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %v, want %v", lines, want)
+	}
+	_, invalid := decodeBatch[handler_dto.CreateSubscriptionRequest](raw)
+	if want := []bool{false, true, true}; !reflect.DeepEqual(invalid, want) {
+		t.Errorf("invalid = %v, want %v", invalid, want)
+	}
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// pauseSubscription godoc
+// @Summary      Pause subscription
+// @Description  Pause an active subscription. Billing dates within a pause are not charged
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      409  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid status transition"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/pause [post]
+func (h *Handler) pauseSubscription(c *gin.Context) {
+	h.changeSubscriptionStatus(c, "pausing", h.service.Subscription.PauseSubscription)
+}
+
+// resumeSubscription godoc
+// @Summary      Resume subscription
+// @Description  Resume a paused subscription
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      409  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid status transition"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/resume [post]
+func (h *Handler) resumeSubscription(c *gin.Context) {
+	h.changeSubscriptionStatus(c, "resuming", h.service.Subscription.ResumeSubscription)
+}
+
+// cancelSubscription godoc
+// @Summary      Cancel subscription
+// @Description  Cancel an active or paused subscription. It stays paid until the end of the current billing period and is not charged afterwards
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      409  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid status transition"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/cancel [post]
+func (h *Handler) cancelSubscription(c *gin.Context) {
+	h.changeSubscriptionStatus(c, "cancelling", h.service.Subscription.CancelSubscription)
+}
+
+func (h *Handler) changeSubscriptionStatus(
+	c *gin.Context,
+	action string,
+	change func(ctx context.Context, id string) (*service_dto.GetSubscriptionOutput, error),
+) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := change(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTransition) {
+			newResponse(c, http.StatusConflict, service.ErrInvalidTransition.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while "+action+" subscription",
+			err,
+			map[string]interface{}{
+				"subscription_id": id,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newGetSubscriptionResponse(res))
+}
@@ -1,14 +1,17 @@
 package v1
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/scmbr/subscription-aggregator/internal/auth"
 	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
 	"github.com/scmbr/subscription-aggregator/internal/service"
 	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
@@ -16,26 +19,48 @@ import (
 )
 
 func (h *Handler) initSubscriptionsRoutes(api *gin.RouterGroup) {
+	read := requireScope(auth.ScopeSubscriptionsRead)
+	write := requireScope(auth.ScopeSubscriptionsWrite)
+	reports := requireScope(auth.ScopeReportsRead)
 	subscriptions := api.Group("/subscriptions")
 	{
-		subscriptions.POST("", h.createSubscription)
-		subscriptions.GET("", h.getAllSubscriptions)
-		subscriptions.GET("/:id", h.getSubscriptionById)
-		subscriptions.PUT("/:id", h.updateSubscriptionById)
-		subscriptions.DELETE("/:id", h.deleteSubscriptionById)
-		subscriptions.GET("/total", h.getSubscriptionTotalPrice)
+		subscriptions.POST("", write, h.createSubscription)
+		subscriptions.GET("", read, h.getAllSubscriptions)
+		subscriptions.POST("/batch", write, h.createSubscriptions)
+		subscriptions.PUT("/batch", write, h.updateSubscriptions)
+		subscriptions.DELETE("/batch", write, h.deleteSubscriptions)
+		subscriptions.POST("/import", write, h.importSubscriptions)
+		subscriptions.GET("/export", read, h.exportSubscriptions)
+		subscriptions.GET("/:id", read, h.getSubscriptionById)
+		subscriptions.PUT("/:id", write, h.updateSubscriptionById)
+		subscriptions.DELETE("/:id", write, h.deleteSubscriptionById)
+		subscriptions.POST("/:id/restore", write, h.restoreSubscription)
+		subscriptions.GET("/:id/history", read, h.getSubscriptionHistory)
+		subscriptions.GET("/:id/prices", read, h.getSubscriptionPrices)
+		subscriptions.POST("/:id/prices", write, h.scheduleSubscriptionPrice)
+		subscriptions.GET("/:id/members", read, h.getSubscriptionMembers)
+		subscriptions.POST("/:id/members", write, h.addSubscriptionMember)
+		subscriptions.DELETE("/:id/members/:user_id", write, h.removeSubscriptionMember)
+		subscriptions.POST("/:id/pause", write, h.pauseSubscription)
+		subscriptions.POST("/:id/resume", write, h.resumeSubscription)
+		subscriptions.POST("/:id/cancel", write, h.cancelSubscription)
+		subscriptions.GET("/total", reports, h.getSubscriptionTotalPrice)
+		subscriptions.GET("/report", reports, h.getSpendReport)
 	}
 }
 
 // createSubscription godoc
 // @Summary      Create subscription
-// @Description  Create a new subscription
+// @Description  Create a new subscription. The service name is resolved to a catalog entry by its name or aliases, ignoring case; unknown services are kept as given. The optional trial end date must lie between the start and end dates; billing dates within the trial are not charged. The currency must be one the exchange rates are configured for
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Accept       json
 // @Produce      json
 // @Param        subscription  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateSubscriptionRequest  true  "Subscription data"
 // @Success      201  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateSubscriptionResponse
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions [post]
 func (h *Handler) createSubscription(c *gin.Context) {
@@ -44,26 +69,31 @@ func (h *Handler) createSubscription(c *gin.Context) {
 		newResponse(c, http.StatusBadRequest, "invalid data")
 		return
 	}
-	var endDate *time.Time
-	if input.EndDate != nil {
-		endDate = &input.EndDate.Time
-	}
 
-	id, err := h.service.Subscription.CreateSubscription(c.Request.Context(), &service_dto.CreateSubscriptionInput{
-		ServiceName: input.ServiceName,
-		Price:       input.Price,
-		UserID:      input.UserID,
-		StartDate:   input.StartDate.Time,
-		EndDate:     endDate,
-	})
+	id, err := h.service.Subscription.CreateSubscription(c.Request.Context(), newCreateSubscriptionInput(&input))
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSubscription) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidSubscription.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			newResponse(c, http.StatusBadRequest, service.ErrUnsupportedCurrency.Error())
+			return
+		}
 		logger.Error("error occurred while creating a subscription", err,
 			map[string]interface{}{
-				"service_name": input.ServiceName,
-				"price":        input.Price,
-				"user_id":      input.UserID,
-				"start_date":   input.StartDate,
-				"end_date":     input.EndDate,
+				"service_name":   input.ServiceName,
+				"price":          input.Price,
+				"currency":       input.Currency,
+				"billing_period": input.BillingPeriod,
+				"user_id":        input.UserID,
+				"start_date":     input.StartDate,
+				"end_date":       input.EndDate,
+				"trial_end_date": input.TrialEndDate,
 			})
 		newResponse(c, http.StatusInternalServerError, "something went wrong")
 		return
@@ -73,15 +103,48 @@ func (h *Handler) createSubscription(c *gin.Context) {
 	})
 }
 
+func newCreateSubscriptionInput(input *handler_dto.CreateSubscriptionRequest) *service_dto.CreateSubscriptionInput {
+	return &service_dto.CreateSubscriptionInput{
+		ServiceName:   input.ServiceName,
+		Price:         input.Price,
+		Currency:      input.Currency,
+		BillingPeriod: input.BillingPeriod,
+		UserID:        input.UserID,
+		StartDate:     input.StartDate.Time,
+		EndDate:       input.EndDate.TimePtr(),
+		TrialEndDate:  input.TrialEndDate.TimePtr(),
+		Tags:          input.Tags,
+	}
+}
+
 // getAllSubscriptions godoc
 // @Summary      Get all subscriptions
-// @Description  Get paginated list of subscriptions
+// @Description  Get paginated list of subscriptions matching the filters. Passing cursor (empty for the first page) switches from offset to keyset pagination by creation time; the next page is requested with next_cursor from the response. The total is only returned in offset mode
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Produce      json
-// @Param        limit   query     int  false  "Limit"   default(20)
-// @Param        offset  query     int  false  "Offset"  default(0)
+// @Param        limit                query     int     false  "Limit"   default(20)
+// @Param        offset               query     int     false  "Offset"  default(0)
+// @Param        cursor               query     string  false  "Opaque page cursor"
+// @Param        user_id              query     string  false  "User ID"  format(uuid)
+// @Param        service_name         query     string  false  "Exact service name"
+// @Param        service_name_prefix  query     string  false  "Case-insensitive service name prefix"
+// @Param        min_price            query     int     false  "Minimum current price"
+// @Param        max_price            query     int     false  "Maximum current price"
+// @Param        active_at            query     string  false  "Active in month (MM-YYYY)"
+// @Param        start_date_from      query     string  false  "Start date from (MM-YYYY)"
+// @Param        start_date_to        query     string  false  "Start date to (MM-YYYY)"
+// @Param        end_date_from        query     string  false  "End date from (MM-YYYY)"
+// @Param        end_date_to          query     string  false  "End date to (MM-YYYY)"
+// @Param        no_end_date          query     bool    false  "Only subscriptions without (true) or with (false) an end date"
+// @Param        trial_ending_within  query     int     false  "Only subscriptions whose trial ends within the given number of days from today"
+// @Param        tag                  query     string  false  "Only subscriptions with the tag"
+// @Param        include_deleted      query     bool    false  "Include deleted subscriptions that are not purged yet; admins only"
+// @Param        sort                 query     string  false  "Sort fields: service_name, price, user_id, start_date, end_date; prefix with - for descending"
 // @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllSubscriptionsResponse
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions [get]
 func (h *Handler) getAllSubscriptions(c *gin.Context) {
@@ -96,52 +159,152 @@ func (h *Handler) getAllSubscriptions(c *gin.Context) {
 		newResponse(c, http.StatusBadRequest, "invalid data")
 		return
 	}
+
+	var input handler_dto.GetAllSubscriptionsRequest
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var cursor *string
+	if value, ok := c.GetQuery("cursor"); ok {
+		cursor = &value
+	}
 	res, err := h.service.Subscription.GetAllSubscriptions(c.Request.Context(), service_dto.GetAllSubscriptionsInput{
+		Filter: subscriptionFilterFromRequest(&input),
+		Sort:   parseSort(input.Sort),
 		Limit:  limit,
 		Offset: offset,
+		Cursor: cursor,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSortField) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidSortField.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCursor) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidCursor.Error())
+			return
+		}
 		logger.Error(
 			"error occurred while getting all subscriptions",
 			err,
 			map[string]interface{}{
 				"limit":  limit,
 				"offset": offset,
+				"sort":   input.Sort,
 			},
 		)
 		newResponse(c, http.StatusInternalServerError, "something went wrong")
 		return
 	}
+	c.JSON(http.StatusOK, newGetAllSubscriptionsResponse(res))
+}
+
+func newGetAllSubscriptionsResponse(res *service_dto.GetAllSubscriptionsOutput) handler_dto.GetAllSubscriptionsResponse {
 	subscriptions := make([]handler_dto.GetSubscriptionResponse, 0, len(res.Subscriptions))
 	for _, i := range res.Subscriptions {
-		var endDate *handler_dto.MonthYear
-		if i.EndDate != nil {
-			endDate = &handler_dto.MonthYear{Time: *i.EndDate}
-		}
-		subscriptions = append(subscriptions, handler_dto.GetSubscriptionResponse{
-			Id:          i.ID,
-			ServiceName: i.ServiceName,
-			Price:       i.Price,
-			UserID:      i.UserID,
-			StartDate:   handler_dto.MonthYear{Time: i.StartDate},
-			EndDate:     endDate,
-		})
+		subscriptions = append(subscriptions, newGetSubscriptionResponse(i))
 	}
-	c.JSON(http.StatusOK, handler_dto.GetAllSubscriptionsResponse{
+	return handler_dto.GetAllSubscriptionsResponse{
 		Total:         res.Total,
 		Subscriptions: subscriptions,
-	})
+		NextCursor:    res.NextCursor,
+	}
+}
+
+func newGetSubscriptionResponse(res *service_dto.GetSubscriptionOutput) handler_dto.GetSubscriptionResponse {
+	var endDate *handler_dto.MonthYear
+	if res.EndDate != nil {
+		endDate = &handler_dto.MonthYear{Time: *res.EndDate}
+	}
+	var trialEndDate *handler_dto.Date
+	if res.TrialEndDate != nil {
+		trialEndDate = &handler_dto.Date{Time: *res.TrialEndDate}
+	}
+	pauses := make([]handler_dto.PauseResponse, 0, len(res.Pauses))
+	for _, p := range res.Pauses {
+		pauses = append(pauses, handler_dto.PauseResponse{
+			StartDate: p.StartDate,
+			EndDate:   p.EndDate,
+		})
+	}
+	return handler_dto.GetSubscriptionResponse{
+		Id:            res.ID,
+		ServiceName:   res.ServiceName,
+		ServiceID:     res.ServiceID,
+		Price:         res.Price,
+		Currency:      res.Currency,
+		BillingPeriod: res.BillingPeriod,
+		MonthlyPrice:  res.MonthlyPrice,
+		UserID:        res.UserID,
+		StartDate:     handler_dto.MonthYear{Time: res.StartDate},
+		EndDate:       endDate,
+		TrialEndDate:  trialEndDate,
+		Status:        res.Status,
+		CancelledAt:   res.CancelledAt,
+		Pauses:        pauses,
+		Tags:          res.Tags,
+		Members:       newMemberResponses(res.Members),
+		DeletedAt:     res.DeletedAt,
+		Version:       res.Version,
+	}
+}
+
+func subscriptionFilterFromRequest(input *handler_dto.GetAllSubscriptionsRequest) service_dto.SubscriptionFilter {
+	return service_dto.SubscriptionFilter{
+		UserID:            input.UserID,
+		ServiceName:       input.ServiceName,
+		ServiceNamePrefix: input.ServiceNamePrefix,
+		MinPrice:          input.MinPrice,
+		MaxPrice:          input.MaxPrice,
+		ActiveAt:          input.ActiveAt.TimePtr(),
+		StartDateFrom:     input.StartDateFrom.TimePtr(),
+		StartDateTo:       input.StartDateTo.TimePtr(),
+		EndDateFrom:       input.EndDateFrom.TimePtr(),
+		EndDateTo:         input.EndDateTo.TimePtr(),
+		NoEndDate:         input.NoEndDate,
+		TrialEndingWithin: input.TrialEndingWithin,
+		Tag:               input.Tag,
+		IncludeDeleted:    input.IncludeDeleted,
+	}
+}
+
+// parseSort parses a "price,-start_date" style list of sort fields.
+func parseSort(sort string) []service_dto.SortField {
+	fields := make([]service_dto.SortField, 0)
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		fields = append(fields, service_dto.SortField{
+			Field: strings.TrimPrefix(field, "-"),
+			Desc:  desc,
+		})
+	}
+	return fields
 }
 
 // getSubscriptionById godoc
 // @Summary      Get subscription by ID
-// @Description  Get subscription details by ID
+// @Description  Get subscription details by ID. The ETag header identifies the version of the subscription and its current price and status; a matching If-None-Match gets 304 Not Modified
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Produce      json
-// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Param        id             path      string  true   "Subscription ID"  format(uuid)
+// @Param        If-None-Match  header    string  false  "ETag of the cached subscription"
 // @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionResponse
+// @Header       200  {string}  ETag  "Version of the subscription and hash of the response"
+// @Success      304
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
 // @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions/{id} [get]
 func (h *Handler) getSubscriptionById(c *gin.Context) {
@@ -152,6 +315,10 @@ func (h *Handler) getSubscriptionById(c *gin.Context) {
 	}
 	res, err := h.service.Subscription.GetSubscriptionById(c.Request.Context(), id)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
 		if errors.Is(err, service.ErrSubscriptionNotFound) {
 			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
 			return
@@ -166,32 +333,39 @@ func (h *Handler) getSubscriptionById(c *gin.Context) {
 		newResponse(c, http.StatusInternalServerError, "something went wrong")
 		return
 	}
-	var endDate *handler_dto.MonthYear
-	if res.EndDate != nil {
-		endDate = &handler_dto.MonthYear{Time: *res.EndDate}
+	body, err := json.Marshal(newGetSubscriptionResponse(res))
+	if err != nil {
+		logger.Error("error occurred while rendering subscription", err, map[string]interface{}{
+			"subscription_id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
 	}
-	subscription := handler_dto.GetSubscriptionResponse{
-		Id:          res.ID,
-		ServiceName: res.ServiceName,
-		Price:       res.Price,
-		UserID:      res.UserID,
-		StartDate:   handler_dto.MonthYear{Time: res.StartDate},
-		EndDate:     endDate,
+	etag := subscriptionETag(res.Version, body)
+	c.Header("ETag", etag)
+	if notModified(c, etag) {
+		c.Status(http.StatusNotModified)
+		return
 	}
-	c.JSON(http.StatusOK, subscription)
+	c.Data(http.StatusOK, gin.MIMEJSON+"; charset=utf-8", body)
 }
 
 // updateSubscriptionById godoc
 // @Summary      Update subscription
-// @Description  Update subscription by ID
+// @Description  Update subscription by ID. A new price is added to the price history instead of overwriting the previous one. The resulting start, end and trial end dates are validated the same way as on creation. With If-Match the update is applied only if the subscription still has the version of that ETag; changes of the current price or status alone do not fail it
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Accept       json
 // @Produce      json
-// @Param        id            path  string  true  "Subscription ID"  format(uuid)
-// @Param        subscription  body  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.UpdateSubscriptionRequest  true  "Updated data"
+// @Param        id            path    string  true   "Subscription ID"  format(uuid)
+// @Param        If-Match      header  string  false  "ETag the subscription is expected to have"
+// @Param        subscription  body    github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.UpdateSubscriptionRequest  true  "Updated data"
 // @Success      204
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
 // @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      412  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription version mismatch"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions/{id} [put]
 func (h *Handler) updateSubscriptionById(c *gin.Context) {
@@ -214,18 +388,54 @@ func (h *Handler) updateSubscriptionById(c *gin.Context) {
 	if input.EndDate != nil {
 		endDate = &input.EndDate.Time
 	}
+
+	var priceEffectiveFrom *time.Time
+	if input.PriceEffectiveFrom != nil {
+		priceEffectiveFrom = &input.PriceEffectiveFrom.Time
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		newResponse(c, http.StatusPreconditionFailed, service.ErrVersionMismatch.Error())
+		return
+	}
 	err := h.service.Subscription.UpdateSubscriptionById(c.Request.Context(), id, &service_dto.UpdateSubscriptionInput{
-		ServiceName: input.ServiceName,
-		Price:       input.Price,
-		UserID:      input.UserID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+		ServiceName:        input.ServiceName,
+		Price:              input.Price,
+		Currency:           input.Currency,
+		BillingPeriod:      input.BillingPeriod,
+		UserID:             input.UserID,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		TrialEndDate:       input.TrialEndDate.TimePtr(),
+		PriceEffectiveFrom: priceEffectiveFrom,
+		Tags:               input.Tags,
+		Version:            version,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
 		if errors.Is(err, service.ErrSubscriptionNotFound) {
 			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
 			return
 		}
+		if errors.Is(err, service.ErrInvalidPriceChange) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidPriceChange.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSubscription) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidSubscription.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			newResponse(c, http.StatusBadRequest, service.ErrUnsupportedCurrency.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			newResponse(c, http.StatusPreconditionFailed, service.ErrVersionMismatch.Error())
+			return
+		}
 		logger.Error(
 			"error occurred while updating subscription by id",
 			err,
@@ -241,13 +451,18 @@ func (h *Handler) updateSubscriptionById(c *gin.Context) {
 
 // deleteSubscriptionById godoc
 // @Summary      Delete subscription
-// @Description  Delete subscription by ID
+// @Description  Delete subscription by ID. The subscription can be restored until it is purged after the retention period. With If-Match the subscription is deleted only if it still has the version of that ETag
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Produce      json
-// @Param        id   path  string  true  "Subscription ID"  format(uuid)
+// @Param        id        path    string  true   "Subscription ID"  format(uuid)
+// @Param        If-Match  header  string  false  "ETag the subscription is expected to have"
 // @Success      204
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
 // @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      412  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription version mismatch"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions/{id} [delete]
 func (h *Handler) deleteSubscriptionById(c *gin.Context) {
@@ -256,12 +471,25 @@ func (h *Handler) deleteSubscriptionById(c *gin.Context) {
 		newResponse(c, http.StatusBadRequest, "invalid data")
 		return
 	}
-	err := h.service.Subscription.DeleteSubscriptionById(c.Request.Context(), id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		newResponse(c, http.StatusPreconditionFailed, service.ErrVersionMismatch.Error())
+		return
+	}
+	err := h.service.Subscription.DeleteSubscriptionById(c.Request.Context(), id, version)
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
 		if errors.Is(err, service.ErrSubscriptionNotFound) {
 			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
 			return
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			newResponse(c, http.StatusPreconditionFailed, service.ErrVersionMismatch.Error())
+			return
+		}
 		logger.Error(
 			"error occurred while deleting subscription by id",
 			err,
@@ -275,18 +503,177 @@ func (h *Handler) deleteSubscriptionById(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// restoreSubscription godoc
+// @Summary      Restore subscription
+// @Description  Restore a deleted subscription that has not been purged yet
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/restore [post]
+func (h *Handler) restoreSubscription(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.RestoreSubscription(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while restoring subscription",
+			err,
+			map[string]interface{}{
+				"subscription_id": id,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newGetSubscriptionResponse(res))
+}
+
+// getSubscriptionPrices godoc
+// @Summary      Get subscription price history
+// @Description  Get all price changes of the subscription, including scheduled ones
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetSubscriptionPricesResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/prices [get]
+func (h *Handler) getSubscriptionPrices(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.GetSubscriptionPrices(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while getting subscription prices",
+			err,
+			map[string]interface{}{
+				"subscription_id": id,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	prices := make([]handler_dto.SubscriptionPriceResponse, 0, len(res))
+	for _, i := range res {
+		prices = append(prices, handler_dto.SubscriptionPriceResponse{
+			Price:         i.Price,
+			EffectiveFrom: handler_dto.MonthYear{Time: i.EffectiveFrom},
+		})
+	}
+	c.JSON(http.StatusOK, handler_dto.GetSubscriptionPricesResponse{
+		Prices: prices,
+	})
+}
+
+// scheduleSubscriptionPrice godoc
+// @Summary      Schedule subscription price change
+// @Description  Add a price change taking effect in the current or a future month
+// @Tags         subscriptions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string  true  "Subscription ID"  format(uuid)
+// @Param        price  body  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.SchedulePriceRequest  true  "Price change"
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/subscriptions/{id}/prices [post]
+func (h *Handler) scheduleSubscriptionPrice(c *gin.Context) {
+	var input handler_dto.SchedulePriceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	err := h.service.Subscription.ScheduleSubscriptionPrice(c.Request.Context(), id, &service_dto.SchedulePriceInput{
+		Price:         input.Price,
+		EffectiveFrom: input.EffectiveFrom.Time,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrSubscriptionNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPriceChange) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidPriceChange.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while scheduling subscription price",
+			err,
+			map[string]interface{}{
+				"subscription_id": id,
+				"price":           input.Price,
+				"effective_from":  input.EffectiveFrom,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 // getSubscriptionTotalPrice godoc
 // @Summary      Get total subscription price
-// @Description  Calculate total price of subscriptions for a given period with optional filters
+// @Description  Calculate total price of subscriptions for a given period with optional filters. Every subscription is charged its price on each of its billing dates within the period; months is the number of months of the period it is billed for and charges the number of billing dates. The by_tag breakdown counts a subscription towards each of its tags. For a single user, subscriptions shared with them are included and only their share of every charge is counted; the balance shows what they paid and what other members owe them
 // @Tags         subscriptions
+// @Security     BearerAuth
 // @Produce      json
 // @Param        user_id       query  string  false  "User ID"       format(uuid)
 // @Param        service_name  query  string  false  "Service name"
-// @Param        start_date    query  string  true   "Period start"  format(date-time)
-// @Param        end_date      query  string  true   "Period end"    format(date-time)
+// @Param        tag           query  string  false  "Tag"
+// @Param        start_date    query  string  true   "Period start (MM-YYYY)"
+// @Param        end_date      query  string  true   "Period end (MM-YYYY)"
+// @Param        currency      query  string  false  "ISO 4217 currency of the total"
 // @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetTotalPriceResponse
 // @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
 // @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "subscription not found"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
 // @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
 // @Router       /api/v1/subscriptions/total [get]
 func (h *Handler) getSubscriptionTotalPrice(c *gin.Context) {
@@ -311,30 +698,93 @@ func (h *Handler) getSubscriptionTotalPrice(c *gin.Context) {
 	if input.EndDate != nil {
 		endDate = &input.EndDate.Time
 	}
+	if endDate.Before(*startDate) {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
 	logger.Debug("handler data:", map[string]interface{}{
 		"user_id":      input.UserID,
 		"service_name": input.ServiceName,
 		"start_date":   startDate,
 		"end_date":     endDate,
 	})
-	total, err := h.service.Subscription.GetSubscriptionsTotalPrice(c.Request.Context(), &service_dto.GetTotalPriceInput{
+	res, err := h.service.Subscription.GetSubscriptionsTotalPrice(c.Request.Context(), &service_dto.GetTotalPriceInput{
 		UserID:      input.UserID,
 		ServiceName: input.ServiceName,
+		Tag:         input.Tag,
 		StartDate:   startDate,
 		EndDate:     endDate,
+		Currency:    input.Currency,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			newResponse(c, http.StatusBadRequest, service.ErrUnsupportedCurrency.Error())
+			return
+		}
 		logger.Error("error occurred while getting total price", err, map[string]interface{}{
 			"user_id":      input.UserID,
 			"service_name": input.ServiceName,
 			"start_date":   input.StartDate,
 			"end_date":     input.EndDate,
+			"currency":     input.Currency,
 		})
 		newResponse(c, http.StatusInternalServerError, "something went wrong")
 		return
 	}
 
+	subscriptions := make([]handler_dto.SubscriptionCostResponse, 0, len(res.Subscriptions))
+	for _, i := range res.Subscriptions {
+		subscriptions = append(subscriptions, handler_dto.SubscriptionCostResponse{
+			Id:            i.ID,
+			ServiceName:   i.ServiceName,
+			Price:         i.Price,
+			Currency:      i.Currency,
+			BillingPeriod: i.BillingPeriod,
+			Tags:          i.Tags,
+			Months:        i.Months,
+			Charges:       i.Charges,
+			Cost:          i.Cost,
+		})
+	}
+	byTag := make([]handler_dto.TagTotalResponse, 0, len(res.ByTag))
+	for _, i := range res.ByTag {
+		byTag = append(byTag, handler_dto.TagTotalResponse{
+			Tag:        i.Tag,
+			TotalPrice: i.TotalPrice,
+			Breakdown:  newCurrencyTotalResponses(i.Breakdown),
+		})
+	}
+	var balance *handler_dto.BalanceResponse
+	if res.Balance != nil {
+		balance = &handler_dto.BalanceResponse{
+			Paid:       res.Balance.Paid,
+			OwedToUser: res.Balance.OwedToUser,
+			OwedByUser: res.Balance.OwedByUser,
+			Net:        res.Balance.OwedToUser - res.Balance.OwedByUser,
+		}
+	}
 	c.JSON(http.StatusOK, handler_dto.GetTotalPriceResponse{
-		TotalPrice: total,
+		TotalPrice:    res.TotalPrice,
+		Currency:      res.Currency,
+		Breakdown:     newCurrencyTotalResponses(res.Breakdown),
+		ByTag:         byTag,
+		Subscriptions: subscriptions,
+		Balance:       balance,
 	})
 }
+
+func newCurrencyTotalResponses(totals []*service_dto.CurrencyTotalOutput) []handler_dto.CurrencyTotalResponse {
+	breakdown := make([]handler_dto.CurrencyTotalResponse, 0, len(totals))
+	for _, i := range totals {
+		breakdown = append(breakdown, handler_dto.CurrencyTotalResponse{
+			Currency:       i.Currency,
+			TotalPrice:     i.TotalPrice,
+			ConvertedPrice: i.ConvertedPrice,
+		})
+	}
+	return breakdown
+}
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := map[string][]service_dto.SortField{
+		"":      {},
+		"price": {{Field: "price"}},
+		"price,-start_date": {
+			{Field: "price"},
+			{Field: "start_date", Desc: true},
+		},
+		" -service_name , ,user_id ": {
+			{Field: "service_name", Desc: true},
+			{Field: "user_id"},
+		},
+	}
+	for sort, want := range tests {
+		if got := parseSort(sort); !reflect.DeepEqual(got, want) {
+			t.Errorf("parseSort(%q) = %+v, want %+v", sort, got, want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+func (h *Handler) initUsersRoutes(api *gin.RouterGroup) {
+	read := requireScope(auth.ScopeSubscriptionsRead)
+	write := requireScope(auth.ScopeSubscriptionsWrite)
+	reports := requireScope(auth.ScopeReportsRead)
+	users := api.Group("/users/:user_id")
+	{
+		users.GET("/subscriptions", read, h.getUserSubscriptions)
+		users.GET("/subscriptions/active", read, h.getUserActiveSubscriptions)
+		users.GET("/subscriptions/count", read, h.getUserSubscriptionsCount)
+		users.GET("/tags", read, h.getUserTags)
+		users.GET("/spend", reports, h.getUserMonthlySpend)
+		users.POST("/calendar-token", write, h.createCalendarToken)
+		users.DELETE("/calendar-token", write, h.revokeCalendarToken)
+	}
+}
+
+// getUserSubscriptions godoc
+// @Summary      Get user subscriptions
+// @Description  Get paginated list of all subscriptions of a user
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true   "User ID"  format(uuid)
+// @Param        limit    query     int     false  "Limit"   default(20)
+// @Param        offset   query     int     false  "Offset"  default(0)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllSubscriptionsResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/subscriptions [get]
+func (h *Handler) getUserSubscriptions(c *gin.Context) {
+	h.listUserSubscriptions(c, false)
+}
+
+// getUserActiveSubscriptions godoc
+// @Summary      Get active user subscriptions
+// @Description  Get paginated list of subscriptions of a user active in the current month
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true   "User ID"  format(uuid)
+// @Param        limit    query     int     false  "Limit"   default(20)
+// @Param        offset   query     int     false  "Offset"  default(0)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllSubscriptionsResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/subscriptions/active [get]
+func (h *Handler) getUserActiveSubscriptions(c *gin.Context) {
+	h.listUserSubscriptions(c, true)
+}
+
+func (h *Handler) listUserSubscriptions(c *gin.Context, activeOnly bool) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.GetUserSubscriptions(c.Request.Context(), userID, service_dto.GetUserSubscriptionsInput{
+		ActiveOnly: activeOnly,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while getting user subscriptions",
+			err,
+			map[string]interface{}{
+				"user_id":     userID,
+				"active_only": activeOnly,
+				"limit":       limit,
+				"offset":      offset,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newGetAllSubscriptionsResponse(res))
+}
+
+// getUserSubscriptionsCount godoc
+// @Summary      Count user subscriptions
+// @Description  Count all subscriptions of a user and those active in the current month
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetUserSubscriptionsCountResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/subscriptions/count [get]
+func (h *Handler) getUserSubscriptionsCount(c *gin.Context) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.CountUserSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while counting user subscriptions",
+			err,
+			map[string]interface{}{
+				"user_id": userID,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, handler_dto.GetUserSubscriptionsCountResponse{
+		Total:  res.Total,
+		Active: res.Active,
+	})
+}
+
+// getUserTags godoc
+// @Summary      Get user tags
+// @Description  Get the tags used by the subscriptions of a user
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetUserTagsResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/tags [get]
+func (h *Handler) getUserTags(c *gin.Context) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.GetUserTags(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while getting user tags",
+			err,
+			map[string]interface{}{
+				"user_id": userID,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, handler_dto.GetUserTagsResponse{
+		Tags: res,
+	})
+}
+
+// getUserMonthlySpend godoc
+// @Summary      Get user monthly spend
+// @Description  Sum the monthly equivalents of the shares of a user in the current prices of the subscriptions the user pays for or is a member of that are active now
+// @Tags         users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id   path   string  true   "User ID"  format(uuid)
+// @Param        currency  query  string  false  "ISO 4217 currency of the total"
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetUserMonthlySpendResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/users/{user_id}/spend [get]
+func (h *Handler) getUserMonthlySpend(c *gin.Context) {
+	userID := c.Param("user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var input handler_dto.GetUserMonthlySpendRequest
+	if err := c.ShouldBindQuery(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	res, err := h.service.Subscription.GetUserMonthlySpend(c.Request.Context(), userID, input.Currency)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) {
+			newResponse(c, http.StatusBadRequest, service.ErrUnsupportedCurrency.Error())
+			return
+		}
+		logger.Error(
+			"error occurred while getting user monthly spend",
+			err,
+			map[string]interface{}{
+				"user_id":  userID,
+				"currency": input.Currency,
+			},
+		)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, handler_dto.GetUserMonthlySpendResponse{
+		MonthlySpend:  res.MonthlySpend,
+		Currency:      res.Currency,
+		Subscriptions: res.Subscriptions,
+		Breakdown:     newCurrencyTotalResponses(res.Breakdown),
+	})
+}
@@ -0,0 +1,290 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	handler_dto "github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto"
+	"github.com/scmbr/subscription-aggregator/internal/service"
+	service_dto "github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+func (h *Handler) initWebhooksRoutes(api *gin.RouterGroup) {
+	webhooks := api.Group("/admin/webhooks")
+	{
+		webhooks.POST("", h.createWebhook)
+		webhooks.GET("", h.getAllWebhooks)
+		webhooks.DELETE("/:id", h.deleteWebhook)
+		webhooks.GET("/:id/deliveries", h.getWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/retry", h.retryWebhookDelivery)
+	}
+}
+
+// createWebhook godoc
+// @Summary      Create webhook
+// @Description  Register an endpoint that receives subscription events of the given types. Every delivery is a POST signed with the secret: the X-Webhook-Signature header is "sha256=" followed by the hex HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body. The secret is returned only in this response
+// @Tags         admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateWebhookRequest  true  "Webhook data"
+// @Success      201    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.CreateWebhookResponse
+// @Failure      400    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500    {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/webhooks [post]
+func (h *Handler) createWebhook(c *gin.Context) {
+	var input handler_dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Webhook.CreateWebhook(c.Request.Context(), &service_dto.CreateWebhookInput{
+		URL:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: input.EventTypes,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidWebhook) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidWebhook.Error())
+			return
+		}
+		logger.Error("error occurred while creating webhook", err, map[string]interface{}{
+			"url": input.URL,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusCreated, handler_dto.CreateWebhookResponse{
+		WebhookResponse: newWebhookResponse(&res.WebhookOutput),
+		Secret:          res.Secret,
+	})
+}
+
+// getAllWebhooks godoc
+// @Summary      Get all webhooks
+// @Description  Get the list of all registered webhook endpoints
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetAllWebhooksResponse
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/webhooks [get]
+func (h *Handler) getAllWebhooks(c *gin.Context) {
+	res, err := h.service.Webhook.GetAllWebhooks(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		logger.Error("error occurred while getting webhooks", err, nil)
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	webhooks := make([]handler_dto.WebhookResponse, 0, len(res))
+	for _, webhook := range res {
+		webhooks = append(webhooks, newWebhookResponse(webhook))
+	}
+	c.JSON(http.StatusOK, handler_dto.GetAllWebhooksResponse{Webhooks: webhooks})
+}
+
+// deleteWebhook godoc
+// @Summary      Delete webhook
+// @Description  Delete a webhook endpoint together with its pending deliveries and delivery log
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Webhook ID"  format(uuid)
+// @Success      204
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "webhook not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/webhooks/{id} [delete]
+func (h *Handler) deleteWebhook(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	if err := h.service.Webhook.DeleteWebhook(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrWebhookNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while deleting webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// getWebhookDeliveries godoc
+// @Summary      Get webhook deliveries
+// @Description  Get the paginated delivery log of a webhook endpoint, newest first. Failed deliveries are retried with exponential backoff and marked dead once they run out of attempts
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id      path      string  true   "Webhook ID"  format(uuid)
+// @Param        status  query     string  false  "Delivery status"  Enums(pending, succeeded, dead)
+// @Param        limit   query     int     false  "Limit"   default(20)
+// @Param        offset  query     int     false  "Offset"  default(0)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.GetWebhookDeliveriesResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "webhook not found"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/webhooks/{id}/deliveries [get]
+func (h *Handler) getWebhookDeliveries(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	var status *string
+	if value, ok := c.GetQuery("status"); ok {
+		status = &value
+	}
+
+	res, err := h.service.Webhook.GetWebhookDeliveries(c.Request.Context(), id, service_dto.GetWebhookDeliveriesInput{
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidDeliveryStatus) {
+			newResponse(c, http.StatusBadRequest, service.ErrInvalidDeliveryStatus.Error())
+			return
+		}
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrWebhookNotFound.Error())
+			return
+		}
+		logger.Error("error occurred while getting webhook deliveries", err, map[string]interface{}{
+			"id": id,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	deliveries := make([]handler_dto.WebhookDeliveryResponse, 0, len(res.Deliveries))
+	for _, delivery := range res.Deliveries {
+		deliveries = append(deliveries, newWebhookDeliveryResponse(delivery))
+	}
+	c.JSON(http.StatusOK, handler_dto.GetWebhookDeliveriesResponse{
+		Total:      res.Total,
+		Deliveries: deliveries,
+	})
+}
+
+// retryWebhookDelivery godoc
+// @Summary      Retry webhook delivery
+// @Description  Queue a dead-lettered delivery again with a fresh set of attempts
+// @Tags         admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id           path      string  true  "Webhook ID"   format(uuid)
+// @Param        delivery_id  path      string  true  "Delivery ID"  format(uuid)
+// @Success      200  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.WebhookDeliveryResponse
+// @Failure      400  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "invalid data"
+// @Failure      401  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "unauthorized"
+// @Failure      403  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "forbidden"
+// @Failure      404  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "webhook delivery not found"
+// @Failure      409  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "webhook delivery is not dead-lettered"
+// @Failure      500  {object}  github.com/scmbr/subscription-aggregator/internal/delivery/http/handler/dto.ErrorResponse  "something went wrong"
+// @Router       /api/v1/admin/webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func (h *Handler) retryWebhookDelivery(c *gin.Context) {
+	id := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+	if _, err := uuid.Parse(id); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid data")
+		return
+	}
+
+	res, err := h.service.Webhook.RetryWebhookDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			newResponse(c, http.StatusForbidden, service.ErrForbidden.Error())
+			return
+		}
+		if errors.Is(err, service.ErrDeliveryNotFound) {
+			newResponse(c, http.StatusNotFound, service.ErrDeliveryNotFound.Error())
+			return
+		}
+		if errors.Is(err, service.ErrDeliveryNotDead) {
+			newResponse(c, http.StatusConflict, service.ErrDeliveryNotDead.Error())
+			return
+		}
+		logger.Error("error occurred while retrying webhook delivery", err, map[string]interface{}{
+			"id":          id,
+			"delivery_id": deliveryID,
+		})
+		newResponse(c, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	c.JSON(http.StatusOK, newWebhookDeliveryResponse(res))
+}
+
+func newWebhookResponse(webhook *service_dto.WebhookOutput) handler_dto.WebhookResponse {
+	return handler_dto.WebhookResponse{
+		Id:         webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery *service_dto.WebhookDeliveryOutput) handler_dto.WebhookDeliveryResponse {
+	return handler_dto.WebhookDeliveryResponse{
+		Id:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
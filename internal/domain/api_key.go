@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// APIKey authenticates service-to-service callers. Only a hash of the key is
+// stored; the plaintext is shown once when the key is created or rotated.
+type APIKey struct {
+	Id        string
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+func NewAPIKey(id, name, prefix, keyHash string, scopes []string) (*APIKey, error) {
+	if name == "" {
+		return nil, fmt.Errorf("domain.NewAPIKey invalid name")
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("domain.NewAPIKey invalid scopes")
+	}
+	return &APIKey{
+		Id:        id,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
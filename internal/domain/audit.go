@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+func (a AuditAction) IsValid() bool {
+	switch a {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore:
+		return true
+	}
+	return false
+}
+
+// AuditEntry records who changed a subscription and how. Before and After
+// are JSON snapshots of the subscription; Before is nil for creations and
+// restorations and After for deletions. Changes maps every field that differs between them to
+// its old and new values.
+type AuditEntry struct {
+	Id             string
+	SubscriptionID string
+	// UserID is the owner of the subscription after the change, or before
+	// it for deletions.
+	UserID string
+	Action AuditAction
+	// Actor identifies the caller, e.g. "user:<id>" or "api_key:<id>".
+	Actor string
+	// OnBehalfOf is the end user a service caller claims to act for.
+	OnBehalfOf *string
+	RequestID  *string
+	Before     []byte
+	After      []byte
+	Changes    []byte
+	OccurredAt time.Time
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type BillingPeriod string
+
+const (
+	BillingPeriodWeekly    BillingPeriod = "weekly"
+	BillingPeriodMonthly   BillingPeriod = "monthly"
+	BillingPeriodQuarterly BillingPeriod = "quarterly"
+	BillingPeriodYearly    BillingPeriod = "yearly"
+)
+
+func (p BillingPeriod) IsValid() bool {
+	switch p {
+	case BillingPeriodWeekly, BillingPeriodMonthly, BillingPeriodQuarterly, BillingPeriodYearly:
+		return true
+	}
+	return false
+}
+
+// next returns the n-th billing date counted from start.
+func (p BillingPeriod) next(start time.Time, n int) time.Time {
+	switch p {
+	case BillingPeriodWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case BillingPeriodQuarterly:
+		return start.AddDate(0, 3*n, 0)
+	case BillingPeriodYearly:
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+// MonthlyPrice normalizes a price charged once per billing period to its
+// monthly equivalent, rounded to the nearest integer.
+func (p BillingPeriod) MonthlyPrice(price int) int {
+	switch p {
+	case BillingPeriodWeekly:
+		return int(math.Round(float64(price) * 52 / 12))
+	case BillingPeriodQuarterly:
+		return int(math.Round(float64(price) / 3))
+	case BillingPeriodYearly:
+		return int(math.Round(float64(price) / 12))
+	default:
+		return price
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventSubscriptionCreated EventType = "subscription.created"
+	EventSubscriptionUpdated EventType = "subscription.updated"
+	EventSubscriptionDeleted EventType = "subscription.deleted"
+	// EventSubscriptionRestored is raised when a deleted subscription is
+	// restored.
+	EventSubscriptionRestored EventType = "subscription.restored"
+	// EventSubscriptionRenewed is raised on every billing date of a
+	// subscription.
+	EventSubscriptionRenewed EventType = "subscription.renewed"
+)
+
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventSubscriptionCreated, EventSubscriptionUpdated, EventSubscriptionDeleted, EventSubscriptionRestored, EventSubscriptionRenewed:
+		return true
+	}
+	return false
+}
+
+// Event records a change of a subscription.
+type Event struct {
+	Id         string
+	Type       EventType
+	OccurredAt time.Time
+	// Subscription is the subscription after the change, or before it for
+	// deletions.
+	Subscription *Subscription
+	// RenewalDate is the billing date of a renewal.
+	RenewalDate *time.Time
+}
+
+func NewSubscriptionEvent(id string, eventType EventType, subscription *Subscription, occurredAt time.Time) *Event {
+	return &Event{
+		Id:           id,
+		Type:         eventType,
+		OccurredAt:   occurredAt,
+		Subscription: subscription,
+	}
+}
+
+// OutboxEvent is an event serialized into the outbox within the transaction
+// of the change that raised it, from where it is published.
+type OutboxEvent struct {
+	Id             string
+	Type           EventType
+	SubscriptionID string
+	// Payload is the JSON body published for the event.
+	Payload     []byte
+	OccurredAt  time.Time
+	Attempts    int
+	PublishedAt *time.Time
+	// ParkedAt is set once the event has failed too many times. Parked
+	// events are kept for inspection but no longer published.
+	ParkedAt *time.Time
+}
+
+// Fail records a failed attempt to publish the event and parks it once it
+// has failed maxAttempts times, so that it stops holding back the events
+// recorded after it.
+func (e *OutboxEvent) Fail(at time.Time, maxAttempts int) {
+	e.Attempts++
+	if e.Attempts >= maxAttempts {
+		e.ParkedAt = &at
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutboxEventFailParksAfterMaxAttempts(t *testing.T) {
+	at := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	event := &OutboxEvent{Id: "event"}
+
+	for attempt := 1; attempt < 3; attempt++ {
+		event.Fail(at, 3)
+		if event.Attempts != attempt || event.ParkedAt != nil {
+			t.Fatalf("after %d failures: attempts %d, parked at %v", attempt, event.Attempts, event.ParkedAt)
+		}
+	}
+	event.Fail(at, 3)
+	if event.Attempts != 3 || event.ParkedAt == nil || !event.ParkedAt.Equal(at) {
+		t.Fatalf("after 3 failures: attempts %d, parked at %v, want parked at %v", event.Attempts, event.ParkedAt, at)
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "fmt"
+
+// Member shares the cost of a subscription paid by another user. A member
+// either pays a fixed amount of every charge or a part of the rest of it
+// proportional to Weight.
+type Member struct {
+	UserID      string
+	Weight      *int
+	FixedAmount *int
+}
+
+func NewMember(userID string, weight, fixedAmount *int) (*Member, error) {
+	if (weight == nil) == (fixedAmount == nil) {
+		return nil, fmt.Errorf("domain.NewMember either weight or fixedAmount must be set")
+	}
+	if weight != nil && *weight <= 0 {
+		return nil, fmt.Errorf("domain.NewMember invalid weight")
+	}
+	if fixedAmount != nil && *fixedAmount < 0 {
+		return nil, fmt.Errorf("domain.NewMember invalid fixedAmount")
+	}
+	return &Member{
+		UserID:      userID,
+		Weight:      weight,
+		FixedAmount: fixedAmount,
+	}, nil
+}
+
+// Shares splits a charge between the payer and the members. Fixed amounts
+// are taken first, capped by what is left of the charge; the rest is split by
+// weight. Unless listed as a member, the payer takes part in the split with a
+// weight of 1, and always covers whatever is left after rounding.
+func (s *Subscription) Shares(charge int) map[string]int {
+	shares := make(map[string]int, len(s.Members)+1)
+	remaining := charge
+	totalWeight := 1
+	for _, m := range s.Members {
+		if m.UserID == s.UserID {
+			totalWeight = 0
+			break
+		}
+	}
+	for _, m := range s.Members {
+		if m.FixedAmount != nil {
+			amount := min(*m.FixedAmount, remaining)
+			shares[m.UserID] += amount
+			remaining -= amount
+			continue
+		}
+		totalWeight += *m.Weight
+	}
+	split := remaining
+	if totalWeight > 0 {
+		for _, m := range s.Members {
+			if m.Weight != nil {
+				amount := split * *m.Weight / totalWeight
+				shares[m.UserID] += amount
+				remaining -= amount
+			}
+		}
+	}
+	shares[s.UserID] += remaining
+	return shares
+}
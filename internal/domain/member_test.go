@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestSubscriptionShares(t *testing.T) {
+	const payer = "payer"
+	tests := []struct {
+		name    string
+		members []Member
+		charge  int
+		want    map[string]int
+	}{
+		{
+			name:   "no members",
+			charge: 1000,
+			want:   map[string]int{payer: 1000},
+		},
+		{
+			name: "weights include the payer",
+			members: []Member{
+				{UserID: "a", Weight: intPtr(1)},
+				{UserID: "b", Weight: intPtr(2)},
+			},
+			charge: 1000,
+			want:   map[string]int{payer: 250, "a": 250, "b": 500},
+		},
+		{
+			name: "fixed amounts are taken before the split",
+			members: []Member{
+				{UserID: "a", FixedAmount: intPtr(300)},
+				{UserID: "b", Weight: intPtr(1)},
+			},
+			charge: 1000,
+			want:   map[string]int{payer: 350, "a": 300, "b": 350},
+		},
+		{
+			name: "fixed amounts are capped by the charge",
+			members: []Member{
+				{UserID: "a", FixedAmount: intPtr(800)},
+				{UserID: "b", FixedAmount: intPtr(500)},
+				{UserID: "c", Weight: intPtr(1)},
+			},
+			charge: 1000,
+			want:   map[string]int{payer: 0, "a": 800, "b": 200, "c": 0},
+		},
+		{
+			name: "the payer covers the rounding remainder",
+			members: []Member{
+				{UserID: "a", Weight: intPtr(1)},
+				{UserID: "b", Weight: intPtr(1)},
+			},
+			charge: 100,
+			want:   map[string]int{payer: 34, "a": 33, "b": 33},
+		},
+		{
+			name: "payer listed with a weight",
+			members: []Member{
+				{UserID: payer, Weight: intPtr(3)},
+				{UserID: "a", Weight: intPtr(1)},
+			},
+			charge: 999,
+			want:   map[string]int{payer: 750, "a": 249},
+		},
+		{
+			name: "payer listed with a fixed amount",
+			members: []Member{
+				{UserID: payer, FixedAmount: intPtr(100)},
+				{UserID: "a", Weight: intPtr(1)},
+			},
+			charge: 1000,
+			want:   map[string]int{payer: 100, "a": 900},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Subscription{UserID: payer, Members: tt.members}
+			got := s.Shares(tt.charge)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Shares(%d) = %v, want %v", tt.charge, got, tt.want)
+			}
+			sum := 0
+			for _, share := range got {
+				sum += share
+			}
+			if sum != tt.charge {
+				t.Errorf("shares sum to %d, want %d", sum, tt.charge)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PriceChange is a subscription price that applies to every billing date
+// starting from EffectiveFrom until the next change.
+type PriceChange struct {
+	Price         int
+	EffectiveFrom time.Time
+}
+
+func NewPriceChange(price int, effectiveFrom time.Time) (*PriceChange, error) {
+	if price < 0 {
+		return nil, fmt.Errorf("domain.NewPriceChange invalid price")
+	}
+	return &PriceChange{
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+	}, nil
+}
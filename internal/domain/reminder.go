@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+type ReminderKind string
+
+const (
+	// ReminderRenewal announces the next charge of a subscription.
+	ReminderRenewal ReminderKind = "renewal"
+	// ReminderEnding announces the last day of a subscription.
+	ReminderEnding ReminderKind = "ending"
+)
+
+// Reminder notifies the payer of a subscription about an upcoming renewal
+// or end. A reminder is identified by its subscription, kind and date.
+type Reminder struct {
+	SubscriptionID string
+	UserID         string
+	ServiceName    string
+	Kind           ReminderKind
+	Date           time.Time
+	Price          int
+	Currency       string
+}
+
+// RemindersBetween returns the reminders due for the days from from to to,
+// both inclusive: the first renewal charged within them and the end of the
+// subscription when it falls within them.
+func (s *Subscription) RemindersBetween(from, to time.Time) []Reminder {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, s.StartDate.Location())
+	until := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, s.StartDate.Location()).AddDate(0, 0, 1)
+	reminders := make([]Reminder, 0)
+	for _, date := range s.BillingDates(from, to) {
+		if !date.Before(from) && date.Before(until) {
+			reminders = append(reminders, s.reminder(ReminderRenewal, date))
+			break
+		}
+	}
+	if last := s.LastBillingDay(); last != nil && !last.Before(from) && last.Before(until) && s.StatusAt(from) != StatusExpired {
+		reminders = append(reminders, s.reminder(ReminderEnding, *last))
+	}
+	return reminders
+}
+
+func (s *Subscription) reminder(kind ReminderKind, date time.Time) Reminder {
+	return Reminder{
+		SubscriptionID: s.Id,
+		UserID:         s.UserID,
+		ServiceName:    s.ServiceName,
+		Kind:           kind,
+		Date:           date,
+		Price:          s.PriceAt(date),
+		Currency:       s.Currency,
+	}
+}
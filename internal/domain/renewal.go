@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// LastBillingDay returns the last day on which the subscription can renew,
+// the end of the month of EndDate, or nil when it has no end date.
+func (s *Subscription) LastBillingDay() *time.Time {
+	if s.EndDate == nil {
+		return nil
+	}
+	last := startOfMonth(*s.EndDate).AddDate(0, 1, -1)
+	return &last
+}
+
+// SkippedBillingDates returns the billing dates that are not charged because
+// they fall within the trial or a finished pause. Dates within an ongoing
+// pause are not included since the pause has no end yet.
+func (s *Subscription) SkippedBillingDates() []time.Time {
+	var until time.Time
+	if s.TrialEndDate != nil {
+		until = s.TrialEndDate.AddDate(0, 0, 1)
+	}
+	for _, p := range s.Pauses {
+		if p.EndDate != nil && p.EndDate.After(until) {
+			until = *p.EndDate
+		}
+	}
+	if last := s.LastBillingDay(); last != nil && last.Before(until) {
+		until = last.AddDate(0, 0, 1)
+	}
+	dates := make([]time.Time, 0)
+	for n := 0; ; n++ {
+		date := s.BillingPeriod.next(s.StartDate, n)
+		if !date.Before(until) {
+			break
+		}
+		if s.InTrial(date) || s.isPaused(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
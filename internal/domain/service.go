@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Service is a catalog entry that subscriptions are matched to by its
+// canonical name or one of its aliases, ignoring case.
+type Service struct {
+	Id           string
+	Name         string
+	Aliases      []string
+	Category     *string
+	DefaultPrice *int
+	LogoURL      *string
+	CreatedAt    time.Time
+}
+
+func NewService(id, name string, aliases []string, category *string, defaultPrice *int, logoURL *string) (*Service, error) {
+	name = NormalizeServiceName(name)
+	if name == "" {
+		return nil, fmt.Errorf("domain.NewService invalid name")
+	}
+	if defaultPrice != nil && *defaultPrice < 0 {
+		return nil, fmt.Errorf("domain.NewService invalid defaultPrice")
+	}
+	seen := map[string]bool{strings.ToLower(name): true}
+	normalized := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = NormalizeServiceName(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		normalized = append(normalized, alias)
+	}
+	return &Service{
+		Id:           id,
+		Name:         name,
+		Aliases:      normalized,
+		Category:     category,
+		DefaultPrice: defaultPrice,
+		LogoURL:      logoURL,
+		CreatedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Names returns the canonical name followed by the aliases.
+func (s *Service) Names() []string {
+	return append([]string{s.Name}, s.Aliases...)
+}
+
+// NormalizeServiceName trims name and collapses inner whitespace.
+func NormalizeServiceName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	// StatusExpired is never stored: a subscription expires once its
+	// EndDate has passed.
+	StatusExpired Status = "expired"
+)
+
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// Pause is an interval during which the subscription is not charged. EndDate
+// is nil while the subscription is still paused.
+type Pause struct {
+	StartDate time.Time
+	EndDate   *time.Time
+}
+
+func (p Pause) contains(t time.Time) bool {
+	return !t.Before(p.StartDate) && (p.EndDate == nil || t.Before(*p.EndDate))
+}
+
+// StatusAt returns the status of the subscription at t.
+func (s *Subscription) StatusAt(t time.Time) Status {
+	if s.Status == StatusCancelled {
+		return StatusCancelled
+	}
+	if s.EndDate != nil && !t.Before(startOfMonth(*s.EndDate).AddDate(0, 1, 0)) {
+		return StatusExpired
+	}
+	if s.Status == "" {
+		return StatusActive
+	}
+	return s.Status
+}
+
+// Pause stops charging an active subscription starting from at.
+func (s *Subscription) Pause(at time.Time) error {
+	if s.StatusAt(at) != StatusActive {
+		return ErrInvalidTransition
+	}
+	s.Status = StatusPaused
+	s.Pauses = append(s.Pauses, Pause{StartDate: at})
+	return nil
+}
+
+// Resume charges a paused subscription again starting from at.
+func (s *Subscription) Resume(at time.Time) error {
+	if s.StatusAt(at) != StatusPaused {
+		return ErrInvalidTransition
+	}
+	s.Status = StatusActive
+	s.closePause(at)
+	return nil
+}
+
+// Cancel stops an active or paused subscription. Nothing is charged after
+// at, and the subscription ends with the billing period that is already paid.
+func (s *Subscription) Cancel(at time.Time) error {
+	if status := s.StatusAt(at); status != StatusActive && status != StatusPaused {
+		return ErrInvalidTransition
+	}
+	s.closePause(at)
+	s.Status = StatusCancelled
+	s.CancelledAt = &at
+
+	end := startOfMonth(s.paidUntil(at))
+	if end.Before(s.StartDate) {
+		end = s.StartDate
+	}
+	if s.EndDate == nil || end.Before(*s.EndDate) {
+		s.EndDate = &end
+	}
+	return nil
+}
+
+// closePause ends the open pause, if any, at t.
+func (s *Subscription) closePause(t time.Time) {
+	if n := len(s.Pauses); n > 0 && s.Pauses[n-1].EndDate == nil {
+		s.Pauses[n-1].EndDate = &t
+	}
+}
+
+// paidUntil returns the last day of the billing period that contains t.
+func (s *Subscription) paidUntil(t time.Time) time.Time {
+	for n := 0; ; n++ {
+		if date := s.BillingPeriod.next(s.StartDate, n); date.After(t) {
+			return date.AddDate(0, 0, -1)
+		}
+	}
+}
+
+// pausedThroughout reports whether a single pause covers both from and to.
+func (s *Subscription) pausedThroughout(from, to time.Time) bool {
+	for _, p := range s.Pauses {
+		if p.contains(from) && p.contains(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPaused reports whether t falls within one of the pauses.
+func (s *Subscription) isPaused(t time.Time) bool {
+	for _, p := range s.Pauses {
+		if p.contains(t) {
+			return true
+		}
+	}
+	return false
+}
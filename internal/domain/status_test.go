@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(year int, m time.Month, d int) time.Time {
+	return time.Date(year, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSubscriptionTransitions(t *testing.T) {
+	at := day(2025, time.March, 10)
+	subscriptions := map[Status]func() *Subscription{
+		StatusActive: func() *Subscription {
+			return &Subscription{StartDate: month(2025, time.January), BillingPeriod: BillingPeriodMonthly}
+		},
+		StatusPaused: func() *Subscription {
+			return &Subscription{
+				StartDate:     month(2025, time.January),
+				BillingPeriod: BillingPeriodMonthly,
+				Status:        StatusPaused,
+				Pauses:        []Pause{{StartDate: day(2025, time.February, 15)}},
+			}
+		},
+		StatusCancelled: func() *Subscription {
+			return &Subscription{
+				StartDate:     month(2025, time.January),
+				EndDate:       datePtr(month(2025, time.June)),
+				BillingPeriod: BillingPeriodMonthly,
+				Status:        StatusCancelled,
+				CancelledAt:   datePtr(day(2025, time.February, 20)),
+			}
+		},
+		StatusExpired: func() *Subscription {
+			return &Subscription{
+				StartDate:     month(2024, time.January),
+				EndDate:       datePtr(month(2025, time.February)),
+				BillingPeriod: BillingPeriodMonthly,
+			}
+		},
+	}
+	actions := map[string]func(*Subscription, time.Time) error{
+		"pause":  (*Subscription).Pause,
+		"resume": (*Subscription).Resume,
+		"cancel": (*Subscription).Cancel,
+	}
+	tests := []struct {
+		from   Status
+		action string
+		to     Status
+		err    error
+	}{
+		{from: StatusActive, action: "pause", to: StatusPaused},
+		{from: StatusActive, action: "resume", err: ErrInvalidTransition},
+		{from: StatusActive, action: "cancel", to: StatusCancelled},
+		{from: StatusPaused, action: "pause", err: ErrInvalidTransition},
+		{from: StatusPaused, action: "resume", to: StatusActive},
+		{from: StatusPaused, action: "cancel", to: StatusCancelled},
+		{from: StatusCancelled, action: "pause", err: ErrInvalidTransition},
+		{from: StatusCancelled, action: "resume", err: ErrInvalidTransition},
+		{from: StatusCancelled, action: "cancel", err: ErrInvalidTransition},
+		{from: StatusExpired, action: "pause", err: ErrInvalidTransition},
+		{from: StatusExpired, action: "resume", err: ErrInvalidTransition},
+		{from: StatusExpired, action: "cancel", err: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+tt.action, func(t *testing.T) {
+			s := subscriptions[tt.from]()
+			before := *s
+			err := actions[tt.action](s, at)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%s() error = %v, want %v", tt.action, err, tt.err)
+			}
+			if tt.err != nil {
+				if !reflect.DeepEqual(*s, before) {
+					t.Errorf("subscription changed by a rejected %s: %+v", tt.action, *s)
+				}
+				return
+			}
+			if got := s.StatusAt(at); got != tt.to {
+				t.Errorf("status = %s, want %s", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestSubscriptionPauseSkipsBillingDates(t *testing.T) {
+	s := &Subscription{StartDate: month(2025, time.January), BillingPeriod: BillingPeriodMonthly}
+	if err := s.Pause(day(2025, time.March, 10)); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if err := s.Resume(day(2025, time.May, 15)); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	want := []time.Time{
+		month(2025, time.January),
+		month(2025, time.February),
+		month(2025, time.March),
+		month(2025, time.June),
+		month(2025, time.July),
+	}
+	if got := s.BillingDates(month(2025, time.January), month(2025, time.July)); !reflect.DeepEqual(got, want) {
+		t.Errorf("BillingDates() = %v, want %v", got, want)
+	}
+	if len(s.Pauses) != 1 || s.Pauses[0].EndDate == nil || !s.Pauses[0].EndDate.Equal(day(2025, time.May, 15)) {
+		t.Errorf("pauses = %+v, want a single pause closed on resume", s.Pauses)
+	}
+}
+
+func TestSubscriptionCancelWhilePaused(t *testing.T) {
+	s := &Subscription{StartDate: month(2025, time.January), BillingPeriod: BillingPeriodMonthly}
+	if err := s.Pause(day(2025, time.March, 10)); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	cancelledAt := day(2025, time.April, 20)
+	if err := s.Cancel(cancelledAt); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	if s.Pauses[0].EndDate == nil || !s.Pauses[0].EndDate.Equal(cancelledAt) {
+		t.Errorf("pause end = %v, want the pause closed at cancellation", s.Pauses[0].EndDate)
+	}
+	if s.CancelledAt == nil || !s.CancelledAt.Equal(cancelledAt) {
+		t.Errorf("cancelled at = %v, want %v", s.CancelledAt, cancelledAt)
+	}
+	// The subscription ends with the billing period containing the
+	// cancellation, which was not charged because of the pause.
+	if s.EndDate == nil || !s.EndDate.Equal(month(2025, time.April)) {
+		t.Errorf("end date = %v, want %v", s.EndDate, month(2025, time.April))
+	}
+	want := []time.Time{
+		month(2025, time.January),
+		month(2025, time.February),
+		month(2025, time.March),
+	}
+	if got := s.BillingDates(month(2025, time.January), month(2025, time.December)); !reflect.DeepEqual(got, want) {
+		t.Errorf("BillingDates() = %v, want %v", got, want)
+	}
+	if err := s.Resume(day(2025, time.May, 1)); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Resume() after cancel error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
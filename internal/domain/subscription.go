@@ -6,27 +6,171 @@ import (
 )
 
 type Subscription struct {
-	Id          string
-	ServiceName string
-	Price       int
-	UserID      string
-	StartDate   time.Time
-	EndDate     *time.Time
+	Id            string
+	ServiceName   string
+	ServiceID     *string
+	Price         int
+	Currency      string
+	BillingPeriod BillingPeriod
+	UserID        string
+	StartDate     time.Time
+	EndDate       *time.Time
+	TrialEndDate  *time.Time
+	CreatedAt     time.Time
+	Status        Status
+	CancelledAt   *time.Time
+	Tags          []string
+	Members       []Member
+	// Prices holds the price history ordered by EffectiveFrom.
+	Prices []PriceChange
+	// Pauses holds the pause intervals ordered by StartDate.
+	Pauses []Pause
+	// DeletedAt is set once the subscription is deleted. Deleted
+	// subscriptions can be restored until they are purged.
+	DeletedAt *time.Time
+	// Version is incremented on every change of the subscription and is
+	// used to detect concurrent updates.
+	Version int64
 }
 
-func NewSubscription(id, serviceName string, price int, userID string, startDate time.Time, endDate *time.Time) (*Subscription, error) {
-	if endDate != nil && endDate.Before(startDate) {
-		return nil, fmt.Errorf("domain.NewSubscription invalid startDate and endDate")
+func NewSubscription(id, serviceName string, price int, currency string, billingPeriod BillingPeriod, userID string, startDate time.Time, endDate, trialEndDate *time.Time) (*Subscription, error) {
+	if err := ValidateDates(startDate, endDate, trialEndDate); err != nil {
+		return nil, err
 	}
 	if price < 0 {
 		return nil, fmt.Errorf("domain.NewSubscription invalid price")
 	}
+	if !isCurrencyCode(currency) {
+		return nil, fmt.Errorf("domain.NewSubscription invalid currency")
+	}
+	if !billingPeriod.IsValid() {
+		return nil, fmt.Errorf("domain.NewSubscription invalid billingPeriod")
+	}
 	return &Subscription{
-		Id:          id,
-		ServiceName: serviceName,
-		Price:       price,
-		UserID:      userID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+		Id:            id,
+		ServiceName:   serviceName,
+		Price:         price,
+		Currency:      currency,
+		BillingPeriod: billingPeriod,
+		UserID:        userID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		TrialEndDate:  trialEndDate,
+		CreatedAt:     time.Now().UTC(),
+		Status:        StatusActive,
+		Tags:          []string{},
+		Prices:        []PriceChange{{Price: price, EffectiveFrom: startDate}},
+		Version:       1,
 	}, nil
 }
+
+// ValidateDates checks that the subscription does not end before it starts
+// and that the trial ends within the subscription.
+func ValidateDates(startDate time.Time, endDate, trialEndDate *time.Time) error {
+	if endDate != nil && endDate.Before(startDate) {
+		return fmt.Errorf("domain.ValidateDates invalid startDate and endDate")
+	}
+	if trialEndDate != nil {
+		if trialEndDate.Before(startDate) {
+			return fmt.Errorf("domain.ValidateDates invalid trialEndDate")
+		}
+		if endDate != nil && !trialEndDate.Before(startOfMonth(*endDate).AddDate(0, 1, 0)) {
+			return fmt.Errorf("domain.ValidateDates invalid trialEndDate")
+		}
+	}
+	return nil
+}
+
+// BillingDates returns the dates within the [from, to] window on which the
+// subscription is charged. Both bounds are inclusive and only their year and
+// month are taken into account; the subscription itself is active until the
+// end of the month of its EndDate. Dates within the trial, within a pause or
+// after the cancellation are not charged.
+func (s *Subscription) BillingDates(from, to time.Time) []time.Time {
+	windowStart := startOfMonth(from)
+	windowEnd := startOfMonth(to).AddDate(0, 1, 0)
+	if s.EndDate != nil {
+		if end := startOfMonth(*s.EndDate).AddDate(0, 1, 0); end.Before(windowEnd) {
+			windowEnd = end
+		}
+	}
+	dates := make([]time.Time, 0)
+	for n := 0; ; n++ {
+		date := s.BillingPeriod.next(s.StartDate, n)
+		if !date.Before(windowEnd) || (s.CancelledAt != nil && date.After(*s.CancelledAt)) {
+			break
+		}
+		if !date.Before(windowStart) && !s.InTrial(date) && !s.isPaused(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// BilledMonths returns the first days of the calendar months of the [from,
+// to] window during which the subscription is active, with the same bounds as
+// BillingDates. Months spent entirely within the trial or a pause are not
+// counted. Unlike BillingDates it does not depend on the billing period: a
+// yearly plan active for the whole window is billed for every month of it but
+// charged only once.
+func (s *Subscription) BilledMonths(from, to time.Time) []time.Time {
+	first := startOfMonth(from)
+	if start := startOfMonth(s.StartDate); start.After(first) {
+		first = start
+	}
+	last := startOfMonth(to)
+	if s.EndDate != nil {
+		if end := startOfMonth(*s.EndDate); end.Before(last) {
+			last = end
+		}
+	}
+	months := make([]time.Time, 0)
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		lastDay := month.AddDate(0, 1, -1)
+		if s.InTrial(lastDay) || s.pausedThroughout(month, lastDay) {
+			continue
+		}
+		months = append(months, month)
+	}
+	return months
+}
+
+// InTrial reports whether t falls within the free trial, which lasts until
+// the end of the TrialEndDate day.
+func (s *Subscription) InTrial(t time.Time) bool {
+	return s.TrialEndDate != nil && t.Before(s.TrialEndDate.AddDate(0, 0, 1))
+}
+
+// PriceAt returns the price in effect at t.
+func (s *Subscription) PriceAt(t time.Time) int {
+	price := s.Price
+	for _, change := range s.Prices {
+		if change.EffectiveFrom.After(t) {
+			break
+		}
+		price = change.Price
+	}
+	return price
+}
+
+// MonthlyPrice returns the monthly equivalent of the price in effect at t.
+func (s *Subscription) MonthlyPrice(t time.Time) int {
+	return s.BillingPeriod.MonthlyPrice(s.PriceAt(t))
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 alphabetic code.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
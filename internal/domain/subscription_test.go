@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestSubscriptionBillingDates(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription Subscription
+		from, to     time.Time
+		want         []time.Time
+	}{
+		{
+			name: "active for the whole window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2024, time.June),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.March),
+			want: []time.Time{month(2025, time.January), month(2025, time.February), month(2025, time.March)},
+		},
+		{
+			name: "starts within the window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.April),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: []time.Time{month(2025, time.April), month(2025, time.May), month(2025, time.June)},
+		},
+		{
+			name: "ends within the window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2024, time.January),
+				EndDate:       datePtr(month(2025, time.February)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: []time.Time{month(2025, time.January), month(2025, time.February)},
+		},
+		{
+			name: "partial first and last months",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+				EndDate:       datePtr(time.Date(2025, time.May, 10, 0, 0, 0, 0, time.UTC)),
+			},
+			from: time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.May, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "ends before the window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2024, time.January),
+				EndDate:       datePtr(month(2024, time.December)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: []time.Time{},
+		},
+		{
+			name: "starts after the window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.July),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: []time.Time{},
+		},
+		{
+			name: "open-ended",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.November),
+			},
+			from: month(2025, time.October),
+			to:   month(2026, time.January),
+			want: []time.Time{month(2025, time.November), month(2025, time.December), month(2026, time.January)},
+		},
+		{
+			name: "yearly",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodYearly,
+				StartDate:     month(2023, time.June),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.December),
+			want: []time.Time{month(2025, time.June)},
+		},
+		{
+			name: "quarterly",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodQuarterly,
+				StartDate:     month(2025, time.February),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.September),
+			want: []time.Time{month(2025, time.February), month(2025, time.May), month(2025, time.August)},
+		},
+		{
+			name: "weekly",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodWeekly,
+				StartDate:     month(2025, time.January),
+				EndDate:       datePtr(month(2025, time.January)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.January),
+			want: []time.Time{
+				month(2025, time.January),
+				time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 22, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.January, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "trial months are not charged",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.January),
+				TrialEndDate:  datePtr(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.April),
+			want: []time.Time{month(2025, time.March), month(2025, time.April)},
+		},
+		{
+			name: "no charges after cancellation",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.January),
+				CancelledAt:   datePtr(time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: []time.Time{month(2025, time.January), month(2025, time.February)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.subscription.BillingDates(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("BillingDates() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("BillingDates() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscriptionBilledMonths(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription Subscription
+		from, to     time.Time
+		want         int
+	}{
+		{
+			name: "yearly plan active for the whole window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodYearly,
+				StartDate:     month(2024, time.June),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.December),
+			want: 12,
+		},
+		{
+			name: "partial first and last months",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.March),
+				EndDate:       datePtr(month(2025, time.May)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.December),
+			want: 3,
+		},
+		{
+			name: "ends before the window",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2024, time.January),
+				EndDate:       datePtr(month(2024, time.December)),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: 0,
+		},
+		{
+			name: "open-ended",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.April),
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: 3,
+		},
+		{
+			name: "months within the trial or a pause are not billed",
+			subscription: Subscription{
+				BillingPeriod: BillingPeriodMonthly,
+				StartDate:     month(2025, time.January),
+				TrialEndDate:  datePtr(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)),
+				Pauses: []Pause{
+					{StartDate: month(2025, time.March), EndDate: datePtr(month(2025, time.May))},
+				},
+			},
+			from: month(2025, time.January),
+			to:   month(2025, time.June),
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(tt.subscription.BilledMonths(tt.from, tt.to)); got != tt.want {
+				t.Errorf("BilledMonths() = %d months, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDates(t *testing.T) {
+	start := month(2025, time.March)
+	tests := []struct {
+		name          string
+		end, trialEnd *time.Time
+		wantErr       bool
+	}{
+		{name: "open-ended"},
+		{name: "ends in the start month", end: datePtr(start)},
+		{name: "ends before it starts", end: datePtr(month(2025, time.February)), wantErr: true},
+		{name: "trial within the subscription", end: datePtr(month(2025, time.May)), trialEnd: datePtr(time.Date(2025, time.May, 31, 0, 0, 0, 0, time.UTC))},
+		{name: "trial ends before the start", trialEnd: datePtr(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)), wantErr: true},
+		{name: "trial ends after the end", end: datePtr(month(2025, time.May)), trialEnd: datePtr(month(2025, time.June)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDates(start, tt.end, tt.trialEnd); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTagLength = 50
+
+// NormalizeTags lowercases and trims every tag, drops duplicates and sorts
+// the result, so that "Work Tools" and "work  tools" are the same tag.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("domain.NormalizeTags invalid tag")
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// SetTags replaces the tags of the subscription.
+func (s *Subscription) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	s.Tags = normalized
+	return nil
+}
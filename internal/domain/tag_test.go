@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "none", tags: nil, want: []string{}},
+		{name: "case and whitespace", tags: []string{"  Work   Tools ", "work tools", "WORK TOOLS"}, want: []string{"work tools"}},
+		{name: "sorted", tags: []string{"music", "Entertainment", "family"}, want: []string{"entertainment", "family", "music"}},
+		{name: "multi-byte runes", tags: []string{"Кино"}, want: []string{"кино"}},
+		{name: "longest", tags: []string{strings.Repeat("ж", 50)}, want: []string{strings.Repeat("ж", 50)}},
+		{name: "too long", tags: []string{strings.Repeat("a", 51)}, wantErr: true},
+		{name: "blank", tags: []string{"music", "  "}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeTags() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTagsKeepsTagsOnError(t *testing.T) {
+	s := &Subscription{Tags: []string{"music"}}
+	if err := s.SetTags([]string{""}); err == nil {
+		t.Fatal("SetTags() accepted a blank tag")
+	}
+	if !reflect.DeepEqual(s.Tags, []string{"music"}) {
+		t.Errorf("tags = %q, want them unchanged", s.Tags)
+	}
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// WebhookEndpoint receives the events of the types it subscribes to. The
+// secret signs every delivery, so it is stored as is.
+type WebhookEndpoint struct {
+	Id         string
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+func NewWebhookEndpoint(id, rawURL, secret string, eventTypes []EventType) (*WebhookEndpoint, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("domain.NewWebhookEndpoint invalid url")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("domain.NewWebhookEndpoint invalid secret")
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("domain.NewWebhookEndpoint invalid eventTypes")
+	}
+	seen := make(map[EventType]bool, len(eventTypes))
+	types := make([]EventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("domain.NewWebhookEndpoint invalid eventTypes")
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return &WebhookEndpoint{
+		Id:         id,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: types,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func (e *WebhookEndpoint) Subscribes(eventType EventType) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDelivery schedules the delivery of the event to the endpoint for now.
+func (e *WebhookEndpoint) NewDelivery(id string, event *OutboxEvent, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		Id:            id,
+		EndpointID:    e.Id,
+		EventID:       event.Id,
+		EventType:     event.Type,
+		Payload:       event.Payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead marks a delivery that failed every attempt and is no
+	// longer retried unless requested.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryPending, DeliverySucceeded, DeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is a single event sent to a single endpoint, with the
+// outcome of its last attempt.
+type WebhookDelivery struct {
+	Id             string
+	EndpointID     string
+	EventID        string
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// RetryPolicy spaces the attempts of a delivery exponentially: the n-th
+// retry waits Backoff * 2^(n-1), at most MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Delay returns the wait before the attempt following the given number of
+// failed attempts.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempts && (p.MaxBackoff == 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// Succeed records a successful attempt.
+func (d *WebhookDelivery) Succeed(at time.Time, statusCode int) {
+	d.Attempts++
+	d.Status = DeliverySucceeded
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.DeliveredAt = &at
+}
+
+// Fail records a failed attempt and schedules the next one, or dead-letters
+// the delivery once the policy runs out of attempts. statusCode is nil when
+// no response was received.
+func (d *WebhookDelivery) Fail(at time.Time, statusCode *int, reason string, policy RetryPolicy) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = &reason
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.NextAttemptAt = at.Add(policy.Delay(d.Attempts))
+}
+
+// Redeliver puts a dead-lettered delivery back into the queue with a fresh
+// set of attempts.
+func (d *WebhookDelivery) Redeliver(at time.Time) error {
+	if d.Status != DeliveryDead {
+		return ErrInvalidTransition
+	}
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	return nil
+}
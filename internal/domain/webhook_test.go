@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 8, Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(tt.attempts); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+	unbounded := RetryPolicy{Backoff: time.Second}
+	if got := unbounded.Delay(11); got != 1024*time.Second {
+		t.Errorf("Delay(11) without MaxBackoff = %v, want %v", got, 1024*time.Second)
+	}
+}
+
+func TestWebhookDeliveryDeadLettering(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}
+	at := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	delivery := (&WebhookEndpoint{Id: "endpoint"}).NewDelivery("delivery", &OutboxEvent{Id: "event"}, at)
+	status := 503
+
+	delivery.Fail(at, &status, "unexpected status 503", policy)
+	if delivery.Status != DeliveryPending || !delivery.NextAttemptAt.Equal(at.Add(time.Minute)) {
+		t.Fatalf("after 1 failure: status %s, next attempt %v", delivery.Status, delivery.NextAttemptAt)
+	}
+	delivery.Fail(at, nil, "connection refused", policy)
+	if delivery.Status != DeliveryPending || !delivery.NextAttemptAt.Equal(at.Add(2*time.Minute)) {
+		t.Fatalf("after 2 failures: status %s, next attempt %v", delivery.Status, delivery.NextAttemptAt)
+	}
+	if delivery.LastStatusCode != nil {
+		t.Errorf("LastStatusCode = %d after a failure without response", *delivery.LastStatusCode)
+	}
+	delivery.Fail(at, &status, "unexpected status 503", policy)
+	if delivery.Status != DeliveryDead || delivery.Attempts != 3 {
+		t.Fatalf("after 3 failures: status %s, attempts %d, want dead after 3", delivery.Status, delivery.Attempts)
+	}
+
+	if err := delivery.Redeliver(at.Add(time.Hour)); err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if delivery.Status != DeliveryPending || delivery.Attempts != 0 || !delivery.NextAttemptAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("after Redeliver: status %s, attempts %d, next attempt %v", delivery.Status, delivery.Attempts, delivery.NextAttemptAt)
+	}
+	if err := delivery.Redeliver(at); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Redeliver() of a pending delivery error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
@@ -0,0 +1,25 @@
+package event
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// LogPublisher writes events to the application log.
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	logger.Info("event published", map[string]interface{}{
+		"id":              event.Id,
+		"type":            event.Type,
+		"subscription_id": event.SubscriptionID,
+		"occurred_at":     event.OccurredAt,
+	})
+	return nil
+}
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// MemoryPublisher keeps the published events in memory, for tests and local
+// runs.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*domain.OutboxEvent
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	published := *event
+	p.events = append(p.events, &published)
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (p *MemoryPublisher) Events() []*domain.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := make([]*domain.OutboxEvent, len(p.events))
+	copy(events, p.events)
+	return events
+}
+
+// Reset forgets the events published so far.
+func (p *MemoryPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = nil
+}
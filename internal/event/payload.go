@@ -0,0 +1,92 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// Payload is the JSON body published for every event.
+type Payload struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       Data      `json:"data"`
+}
+type Data struct {
+	Subscription Subscription `json:"subscription"`
+	// Renewal is set for subscription.renewed events only.
+	Renewal *Renewal `json:"renewal,omitempty"`
+}
+
+// Subscription is the state of the subscription when the event occurred.
+// Start and end dates are formatted as MM-YYYY like in the API.
+type Subscription struct {
+	ID            string   `json:"subscription_id"`
+	ServiceName   string   `json:"service_name"`
+	Price         int      `json:"price"`
+	Currency      string   `json:"currency"`
+	BillingPeriod string   `json:"billing_period"`
+	UserID        string   `json:"user_id"`
+	StartDate     string   `json:"start_date"`
+	EndDate       *string  `json:"end_date"`
+	Status        string   `json:"status"`
+	Tags          []string `json:"tags"`
+}
+type Renewal struct {
+	Date     string `json:"date"`
+	Price    int    `json:"price"`
+	Currency string `json:"currency"`
+}
+
+// Encode renders the event into the form stored in the outbox and handed
+// to publishers.
+func Encode(event *domain.Event) (*domain.OutboxEvent, error) {
+	s := event.Subscription
+	var endDate *string
+	if s.EndDate != nil {
+		date := s.EndDate.Format("01-2006")
+		endDate = &date
+	}
+	tags := make([]string, 0, len(s.Tags))
+	tags = append(tags, s.Tags...)
+	payload := Payload{
+		ID:         event.Id,
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt,
+		Data: Data{
+			Subscription: Subscription{
+				ID:            s.Id,
+				ServiceName:   s.ServiceName,
+				Price:         s.PriceAt(event.OccurredAt),
+				Currency:      s.Currency,
+				BillingPeriod: string(s.BillingPeriod),
+				UserID:        s.UserID,
+				StartDate:     s.StartDate.Format("01-2006"),
+				EndDate:       endDate,
+				Status:        string(s.StatusAt(event.OccurredAt)),
+				Tags:          tags,
+			},
+		},
+	}
+	if event.RenewalDate != nil {
+		payload.Data.Renewal = &Renewal{
+			Date:     event.RenewalDate.Format("2006-01-02"),
+			Price:    s.PriceAt(*event.RenewalDate),
+			Currency: s.Currency,
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("event.Encode: %w", err)
+	}
+	return &domain.OutboxEvent{
+		Id:             event.Id,
+		Type:           event.Type,
+		SubscriptionID: s.Id,
+		Payload:        data,
+		OccurredAt:     event.OccurredAt,
+	}, nil
+}
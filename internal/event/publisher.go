@@ -0,0 +1,29 @@
+package event
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// Publisher hands events over to their consumers. The outbox relay publishes
+// every event at least once, so a publisher may see an event again after a
+// crash or a failed publish and must tolerate duplicates, which share the
+// event ID.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// Publishers publishes every event to each of the publishers in turn,
+// stopping at the first failure. The event is then published again to all
+// of them.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+)
+
+// RateProvider returns exchange rates between ISO 4217 currencies.
+type RateProvider interface {
+	// Rate returns how many units of the to currency one unit of the from
+	// currency is worth.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
@@ -0,0 +1,39 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StaticRates is a RateProvider backed by a fixed table of rates, each
+// expressed as the value of one unit of a currency in the base currency.
+type StaticRates struct {
+	base  string
+	rates map[string]float64
+}
+
+func NewStaticRates(base string, rates map[string]float64) *StaticRates {
+	base = strings.ToUpper(base)
+	normalized := make(map[string]float64, len(rates)+1)
+	for currency, rate := range rates {
+		normalized[strings.ToUpper(currency)] = rate
+	}
+	normalized[base] = 1
+	return &StaticRates{
+		base:  base,
+		rates: normalized,
+	}
+}
+
+func (r *StaticRates) Rate(ctx context.Context, from, to string) (float64, error) {
+	fromRate, ok := r.rates[strings.ToUpper(from)]
+	if !ok || fromRate <= 0 {
+		return 0, fmt.Errorf("staticRates.Rate %s: %w", from, ErrUnknownCurrency)
+	}
+	toRate, ok := r.rates[strings.ToUpper(to)]
+	if !ok || toRate <= 0 {
+		return 0, fmt.Errorf("staticRates.Rate %s: %w", to, ErrUnknownCurrency)
+	}
+	return fromRate / toRate, nil
+}
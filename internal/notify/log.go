@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/pkg/logger"
+)
+
+// LogNotifier only logs reminders, for development and deployments without
+// a mail server.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	logger.Info(subject(reminder), map[string]interface{}{
+		"subscription_id": reminder.SubscriptionID,
+		"user_id":         reminder.UserID,
+		"kind":            reminder.Kind,
+		"date":            reminder.Date.Format("2006-01-02"),
+	})
+	return nil
+}
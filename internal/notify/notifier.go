@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// Notifier delivers reminders to the payers of subscriptions.
+type Notifier interface {
+	Notify(ctx context.Context, reminder *domain.Reminder) error
+}
+
+// subject and body render the text of a reminder.
+func subject(reminder *domain.Reminder) string {
+	if reminder.Kind == domain.ReminderEnding {
+		return fmt.Sprintf("%s subscription ends on %s", reminder.ServiceName, reminder.Date.Format("02.01.2006"))
+	}
+	return fmt.Sprintf("%s subscription renews on %s", reminder.ServiceName, reminder.Date.Format("02.01.2006"))
+}
+
+func body(reminder *domain.Reminder) string {
+	if reminder.Kind == domain.ReminderEnding {
+		return fmt.Sprintf("Your %s subscription ends on %s and will not be renewed.\r\n",
+			reminder.ServiceName, reminder.Date.Format("02.01.2006"))
+	}
+	return fmt.Sprintf("Your %s subscription renews on %s for %d %s.\r\n",
+		reminder.ServiceName, reminder.Date.Format("02.01.2006"), reminder.Price, reminder.Currency)
+}
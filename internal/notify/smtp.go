@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// To is the recipient address with "{user_id}" standing for the ID of
+	// the user, since users are known to the service by their IDs only.
+	To string
+	// Timeout bounds a whole delivery, from dialing to QUIT. Zero means
+	// that only the deadline of the context applies.
+	Timeout time.Duration
+}
+
+// SMTPNotifier mails reminders through an SMTP server.
+type SMTPNotifier struct {
+	cfg  SMTPConfig
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return &SMTPNotifier{
+		cfg:  cfg,
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		auth: auth,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	to := strings.ReplaceAll(n.cfg.To, "{user_id}", reminder.UserID)
+	if err := n.send(ctx, to, n.message(to, reminder)); err != nil {
+		return fmt.Errorf("smtpNotifier.Notify: %w", err)
+	}
+	return nil
+}
+
+// send delivers msg like smtp.SendMail, but over a connection whose
+// deadline follows the context and the configured timeout, so that a
+// stuck server cannot block the caller.
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	if n.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.cfg.Timeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) message(to string, reminder *domain.Reminder) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(reminder)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(body(reminder))
+	return []byte(b.String())
+}
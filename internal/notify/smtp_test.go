@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// fakeMail is a message received by fakeSMTPServer.
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts connections on a local port and speaks just enough
+// SMTP to take one message per connection. With stuck set it accepts
+// connections but never greets the client.
+func fakeSMTPServer(t *testing.T, stuck bool) (host, port string, mails <-chan fakeMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	ch := make(chan fakeMail, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if stuck {
+				go func() {
+					defer conn.Close()
+					io.Copy(io.Discard, conn)
+				}()
+				continue
+			}
+			go serveSMTP(conn, ch)
+		}
+	}()
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port, ch
+}
+
+func serveSMTP(conn net.Conn, mails chan<- fakeMail) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake ESMTP")
+	var mail fakeMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail.from = address(line[len("MAIL FROM:"):])
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			mail.to = append(mail.to, address(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			mails <- mail
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// address returns the path of a MAIL or RCPT command without the angle
+// brackets and parameters.
+func address(path string) string {
+	path, _, _ = strings.Cut(path, ">")
+	return strings.TrimPrefix(path, "<")
+}
+
+func TestSMTPNotifierSendsReminder(t *testing.T) {
+	host, port, mails := fakeSMTPServer(t, false)
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Host:    host,
+		Port:    port,
+		From:    "reminders@example.com",
+		To:      "{user_id}@users.example.com",
+		Timeout: 5 * time.Second,
+	})
+	reminder := &domain.Reminder{
+		SubscriptionID: "3f8c3a4e-8d8a-4f55-9a4c-2b1f6a1c9d10",
+		UserID:         "60601fee-2bf1-4721-ae6f-7636e79a0cba",
+		ServiceName:    "Yandex Plus",
+		Kind:           domain.ReminderRenewal,
+		Date:           time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+		Price:          400,
+		Currency:       "RUB",
+	}
+
+	if err := notifier.Notify(context.Background(), reminder); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	var mail fakeMail
+	select {
+	case mail = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+
+	wantTo := "60601fee-2bf1-4721-ae6f-7636e79a0cba@users.example.com"
+	if mail.from != "reminders@example.com" {
+		t.Errorf("MAIL FROM = %q", mail.from)
+	}
+	if len(mail.to) != 1 || mail.to[0] != wantTo {
+		t.Errorf("RCPT TO = %q, want [%q]", mail.to, wantTo)
+	}
+	for _, want := range []string{
+		"To: " + wantTo + "\r\n",
+		"Subject: Yandex Plus subscription renews on 01.07.2025\r\n",
+		"Your Yandex Plus subscription renews on 01.07.2025 for 400 RUB.\r\n",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, mail.data)
+		}
+	}
+}
+
+func TestSMTPNotifierTimesOutOnStuckServer(t *testing.T) {
+	host, port, _ := fakeSMTPServer(t, true)
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Host:    host,
+		Port:    port,
+		From:    "reminders@example.com",
+		To:      "{user_id}@users.example.com",
+		Timeout: 100 * time.Millisecond,
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- notifier.Notify(context.Background(), &domain.Reminder{UserID: "user"})
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Notify() error = nil, want a timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify() blocked on a stuck server")
+	}
+}
+
+func TestSMTPNotifierStopsWithContext(t *testing.T) {
+	host, port, _ := fakeSMTPServer(t, true)
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "reminders@example.com",
+		To:   "{user_id}@users.example.com",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- notifier.Notify(ctx, &domain.Reminder{UserID: "user"})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Notify() error = nil, want an error after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify() ignored the cancellation of its context")
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// apiKeyColumns selects scopes as a space separated string.
+const apiKeyColumns = "id, name, prefix, key_hash, array_to_string(scopes, ' ') AS scopes, created_at, rotated_at, revoked_at"
+
+type APIKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepo {
+	return &APIKeyRepo{
+		db: db,
+	}
+}
+
+func (r *APIKeyRepo) Create(ctx context.Context, input *domain.APIKey) error {
+	_, err := r.db.ExecContext(ctx, `
+    INSERT INTO api_keys (id, name, prefix, key_hash, scopes, created_at)
+    VALUES ($1, $2, $3, $4, string_to_array($5, ' '), $6)
+`, input.Id, input.Name, input.Prefix, input.KeyHash, strings.Join(input.Scopes, " "), input.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("apiKeyRepo.Create:%w", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	keys := make([]*models.APIKey, 0)
+	query := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at, id"
+	if err := r.db.SelectContext(ctx, &keys, query); err != nil {
+		return nil, fmt.Errorf("apiKeyRepo.GetAll: %w", err)
+	}
+	keysDomain := make([]*domain.APIKey, 0, len(keys))
+	for _, k := range keys {
+		keysDomain = append(keysDomain, models.APIKeyModelToDomain(k))
+	}
+	return keysDomain, nil
+}
+
+func (r *APIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	var key models.APIKey
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE prefix = $1"
+	if err := r.db.GetContext(ctx, &key, query, prefix); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("apiKeyRepo.GetByPrefix: %w", err)
+	}
+	return models.APIKeyModelToDomain(&key), nil
+}
+
+func (r *APIKeyRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", revokedAt, id)
+	if err != nil {
+		return fmt.Errorf("apiKeyRepo.Revoke: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Rotate replaces the key material of an active key, invalidating the
+// previous plaintext.
+func (r *APIKeyRepo) Rotate(ctx context.Context, id, prefix, keyHash string, rotatedAt time.Time) (*domain.APIKey, error) {
+	var key models.APIKey
+	query := `
+		UPDATE api_keys SET prefix = $1, key_hash = $2, rotated_at = $3
+		WHERE id = $4 AND revoked_at IS NULL
+		RETURNING ` + apiKeyColumns
+	if err := r.db.GetContext(ctx, &key, query, prefix, keyHash, rotatedAt, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("apiKeyRepo.Rotate: %w", err)
+	}
+	return models.APIKeyModelToDomain(&key), nil
+}
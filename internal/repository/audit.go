@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/audit"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+const auditEntryColumns = `id, subscription_id, user_id, action, actor, on_behalf_of, request_id,
+	before_state, after_state, changes, occurred_at`
+
+// AuditRepo reads the audit log of subscription changes. Entries are
+// appended by the subscription writes within their own transactions, so an
+// entry is stored if and only if its change is. The log is append-only: the
+// database rejects updates and deletions of its rows.
+type AuditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepo {
+	return &AuditRepo{
+		db: db,
+	}
+}
+
+// recordAudit appends the change of a subscription from before to after,
+// both read within the transaction of the change, to the log. The change is
+// attributed to the caller in ctx. before is nil for creations and
+// restorations and after for deletions.
+func recordAudit(ctx context.Context, e sqlx.ExecerContext, action domain.AuditAction, before, after *domain.Subscription) error {
+	entry, err := audit.NewEntry(ctx, uuid.NewString(), action, audit.NewSnapshot(before), audit.NewSnapshot(after), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = e.ExecContext(ctx, `
+		INSERT INTO audit_log (`+auditEntryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, entry.Id, entry.SubscriptionID, entry.UserID, string(entry.Action), entry.Actor, entry.OnBehalfOf, entry.RequestID,
+		jsonOrNil(entry.Before), jsonOrNil(entry.After), string(entry.Changes), entry.OccurredAt)
+	return err
+}
+
+// jsonOrNil passes JSON as text, and nil as NULL.
+func jsonOrNil(data []byte) *string {
+	if data == nil {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
+// GetAll returns a page of the entries matching the filter, newest first,
+// and their total count.
+func (r *AuditRepo) GetAll(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]*domain.AuditEntry, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.SubscriptionID != nil {
+		where = append(where, "subscription_id = ?")
+		args = append(args, *filter.SubscriptionID)
+	}
+	if filter.UserID != nil {
+		where = append(where, "user_id = ?")
+		args = append(args, *filter.UserID)
+	}
+	if filter.Actor != nil {
+		where = append(where, "actor = ?")
+		args = append(args, *filter.Actor)
+	}
+	if filter.Action != nil {
+		where = append(where, "action = ?")
+		args = append(args, *filter.Action)
+	}
+	if filter.From != nil {
+		where = append(where, "occurred_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		where = append(where, "occurred_at < ?")
+		args = append(args, *filter.To)
+	}
+	query := "SELECT " + auditEntryColumns + " FROM audit_log WHERE " + strings.Join(where, " AND ")
+	countQuery := "SELECT COUNT(*) FROM audit_log WHERE " + strings.Join(where, " AND ")
+	countArgs := append([]interface{}{}, args...)
+
+	query += " ORDER BY occurred_at DESC, id"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	if offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, offset)
+	}
+
+	entries := make([]*models.AuditEntry, 0)
+	if err := r.db.SelectContext(ctx, &entries, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, 0, fmt.Errorf("auditRepo.GetAll: %w", err)
+	}
+	var count int
+	if err := r.db.GetContext(ctx, &count, sqlx.Rebind(sqlx.DOLLAR, countQuery), countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("auditRepo.GetAll: %w", err)
+	}
+
+	entriesDomain := make([]*domain.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		entriesDomain = append(entriesDomain, models.AuditEntryModelToDomain(e))
+	}
+	return entriesDomain, count, nil
+}
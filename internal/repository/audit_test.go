@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/audit"
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// recordingExecer captures the statements executed through it and fails
+// them with err when set.
+type recordingExecer struct {
+	queries []string
+	args    [][]interface{}
+	err     error
+}
+
+func (e *recordingExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.args = append(e.args, args)
+	return nil, e.err
+}
+
+func auditFixture() *domain.Subscription {
+	return &domain.Subscription{
+		Id:            "3f8c3a4e-8d8a-4f55-9a4c-2b1f6a1c9d10",
+		ServiceName:   "Yandex Plus",
+		Price:         400,
+		Currency:      "RUB",
+		BillingPeriod: domain.BillingPeriodMonthly,
+		UserID:        "60601fee-2bf1-4721-ae6f-7636e79a0cba",
+		StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Status:        domain.StatusActive,
+	}
+}
+
+func TestRecordAudit(t *testing.T) {
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "60601fee-2bf1-4721-ae6f-7636e79a0cba"})
+	ctx = audit.WithMetadata(ctx, audit.Metadata{RequestID: "request"})
+	before := auditFixture()
+	after := auditFixture()
+	after.ServiceName = "Kinopoisk"
+
+	e := &recordingExecer{}
+	if err := recordAudit(ctx, e, domain.AuditActionUpdate, before, after); err != nil {
+		t.Fatalf("recordAudit() error = %v", err)
+	}
+	if len(e.queries) != 1 || !strings.Contains(e.queries[0], "INSERT INTO audit_log") {
+		t.Fatalf("queries = %q, want a single insert into audit_log", e.queries)
+	}
+	// The arguments follow auditEntryColumns.
+	args := e.args[0]
+	if args[1] != before.Id || args[2] != after.UserID || args[3] != string(domain.AuditActionUpdate) {
+		t.Errorf("subscription, user and action = %v, %v, %v", args[1], args[2], args[3])
+	}
+	if args[4] != "user:60601fee-2bf1-4721-ae6f-7636e79a0cba" {
+		t.Errorf("actor = %v", args[4])
+	}
+	if requestID, ok := args[6].(*string); !ok || requestID == nil || *requestID != "request" {
+		t.Errorf("request ID = %v, want request", args[6])
+	}
+	var changes map[string]audit.Change
+	if err := json.Unmarshal([]byte(args[9].(string)), &changes); err != nil {
+		t.Fatalf("changes: %v", err)
+	}
+	if len(changes) != 1 || string(changes["service_name"].To) != `"Kinopoisk"` {
+		t.Errorf("changes = %v, want only service_name", changes)
+	}
+}
+
+func TestRecordAuditDeletion(t *testing.T) {
+	e := &recordingExecer{}
+	if err := recordAudit(context.Background(), e, domain.AuditActionDelete, auditFixture(), nil); err != nil {
+		t.Fatalf("recordAudit() error = %v", err)
+	}
+	args := e.args[0]
+	if args[4] != "system" {
+		t.Errorf("actor without a caller = %v, want system", args[4])
+	}
+	if before, ok := args[7].(*string); !ok || before == nil {
+		t.Errorf("before state = %v, want the deleted state", args[7])
+	}
+	if after, ok := args[8].(*string); !ok || after != nil {
+		t.Errorf("after state = %v, want NULL", args[8])
+	}
+}
+
+func TestRecordAuditFailsWithInsert(t *testing.T) {
+	insertErr := errors.New("audit_log is not writable")
+	e := &recordingExecer{err: insertErr}
+	err := recordAudit(context.Background(), e, domain.AuditActionCreate, nil, auditFixture())
+	if !errors.Is(err, insertErr) {
+		t.Errorf("recordAudit() error = %v, want %v", err, insertErr)
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CalendarRepo stores the hashes of the tokens protecting the calendar
+// feeds of users, one token per user.
+type CalendarRepo struct {
+	db *sqlx.DB
+}
+
+func NewCalendarRepository(db *sqlx.DB) *CalendarRepo {
+	return &CalendarRepo{
+		db: db,
+	}
+}
+
+// SetToken stores the token of the user, replacing the previous one.
+func (r *CalendarRepo) SetToken(ctx context.Context, userID, tokenHash string, createdAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO calendar_feeds (user_id, token_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at
+	`, userID, tokenHash, createdAt)
+	if err != nil {
+		return fmt.Errorf("calendarRepo.SetToken: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarRepo) GetTokenHash(ctx context.Context, userID string) (string, error) {
+	var hash string
+	if err := r.db.GetContext(ctx, &hash, "SELECT token_hash FROM calendar_feeds WHERE user_id = $1", userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("calendarRepo.GetTokenHash: %w", err)
+	}
+	return hash, nil
+}
+
+func (r *CalendarRepo) DeleteToken(ctx context.Context, userID string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM calendar_feeds WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("calendarRepo.DeleteToken: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -4,4 +4,6 @@ import "errors"
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrConflict means the row was changed concurrently.
+	ErrConflict = errors.New("conflict")
 )
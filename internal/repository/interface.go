@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/scmbr/subscription-aggregator/internal/domain"
@@ -10,18 +11,92 @@ import (
 
 type SubscriptionRepository interface {
 	Create(ctx context.Context, input *domain.Subscription) error
-	GetAll(ctx context.Context, limit, ofset int) ([]*domain.Subscription, int, error)
+	GetAll(ctx context.Context, filter models.SubscriptionFilter, sort []models.SortField, limit, offset int) ([]*domain.Subscription, int, error)
+	GetPage(ctx context.Context, filter models.SubscriptionFilter, after *models.SubscriptionKey, limit int) ([]*domain.Subscription, error)
+	Stream(ctx context.Context, filter models.SubscriptionFilter, fn func(*domain.Subscription) error) error
+	GetByUserID(ctx context.Context, userID string, activeAt *time.Time, limit, offset int) ([]*domain.Subscription, error)
+	CountByUserID(ctx context.Context, userID string, activeAt time.Time) (*models.UserSubscriptionsCount, error)
 	GetById(ctx context.Context, id string) (*domain.Subscription, error)
+	GetDeletedById(ctx context.Context, id string) (*domain.Subscription, error)
 	Update(ctx context.Context, id string, input models.SubscriptionUpdate) error
+	Delete(ctx context.Context, id string, version *int64) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	CreateBatch(ctx context.Context, input []*domain.Subscription, atomic bool) ([]error, error)
+	UpdateBatch(ctx context.Context, input []models.SubscriptionBatchUpdate, atomic bool) ([]error, error)
+	DeleteBatch(ctx context.Context, ids []string, atomic bool) ([]error, error)
+	GetPrices(ctx context.Context, id string) ([]domain.PriceChange, error)
+	AddPrice(ctx context.Context, id string, input *domain.PriceChange) error
+	UpdateStatus(ctx context.Context, input *domain.Subscription, from domain.Status) error
+	GetTagsByUserID(ctx context.Context, userID string) ([]string, error)
+	AddMember(ctx context.Context, id string, input *domain.Member) error
+	RemoveMember(ctx context.Context, id, userID string) error
+	GetForPeriod(ctx context.Context, filter models.GetTotalPriceFilter) ([]*domain.Subscription, error)
+}
+type APIKeyRepository interface {
+	Create(ctx context.Context, input *domain.APIKey) error
+	GetAll(ctx context.Context) ([]*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	Rotate(ctx context.Context, id, prefix, keyHash string, rotatedAt time.Time) (*domain.APIKey, error)
+}
+type ServiceRepository interface {
+	Create(ctx context.Context, input *domain.Service) error
+	GetAll(ctx context.Context) ([]*domain.Service, error)
+	GetById(ctx context.Context, id string) (*domain.Service, error)
+	FindByName(ctx context.Context, name string) (*domain.Service, error)
+	Update(ctx context.Context, input *domain.Service) error
 	Delete(ctx context.Context, id string) error
-	GetTotalPrice(ctx context.Context, filter models.GetTotalPriceFilter) (int, error)
+}
+type CalendarRepository interface {
+	SetToken(ctx context.Context, userID, tokenHash string, createdAt time.Time) error
+	GetTokenHash(ctx context.Context, userID string) (string, error)
+	DeleteToken(ctx context.Context, userID string) error
+}
+type NotificationRepository interface {
+	Claim(ctx context.Context, reminder *domain.Reminder, sentAt time.Time) (bool, error)
+	Release(ctx context.Context, reminder *domain.Reminder) error
+}
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, input *domain.WebhookEndpoint) error
+	GetEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	GetEndpointsByEventType(ctx context.Context, eventType domain.EventType) ([]*domain.WebhookEndpoint, error)
+	GetEndpointById(ctx context.Context, id string) (*domain.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	CreateDeliveries(ctx context.Context, input []*domain.WebhookDelivery) error
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, input *domain.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter, limit, offset int) ([]*domain.WebhookDelivery, int, error)
+	GetDeliveryById(ctx context.Context, endpointID, id string) (*domain.WebhookDelivery, error)
+}
+type OutboxRepository interface {
+	Add(ctx context.Context, events []*domain.Event) error
+	Relay(ctx context.Context, limit, maxAttempts int, publish func(*domain.OutboxEvent) error) (int, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+type AuditRepository interface {
+	GetAll(ctx context.Context, filter models.AuditFilter, limit, offset int) ([]*domain.AuditEntry, int, error)
 }
 type Repository struct {
 	Subscription SubscriptionRepository
+	APIKey       APIKeyRepository
+	Service      ServiceRepository
+	Calendar     CalendarRepository
+	Notification NotificationRepository
+	Webhook      WebhookRepository
+	Outbox       OutboxRepository
+	Audit        AuditRepository
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
 		Subscription: NewSubscriptionRepository(db),
+		APIKey:       NewAPIKeyRepository(db),
+		Service:      NewServiceRepository(db),
+		Calendar:     NewCalendarRepository(db),
+		Notification: NewNotificationRepository(db),
+		Webhook:      NewWebhookRepository(db),
+		Outbox:       NewOutboxRepository(db),
+		Audit:        NewAuditRepository(db),
 	}
 }
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type APIKey struct {
+	Id        string     `db:"id"`
+	Name      string     `db:"name"`
+	Prefix    string     `db:"prefix"`
+	KeyHash   string     `db:"key_hash"`
+	Scopes    string     `db:"scopes"`
+	CreatedAt time.Time  `db:"created_at"`
+	RotatedAt *time.Time `db:"rotated_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+func APIKeyModelToDomain(m *APIKey) *domain.APIKey {
+	return &domain.APIKey{
+		Id:        m.Id,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		KeyHash:   m.KeyHash,
+		Scopes:    strings.Fields(m.Scopes),
+		CreatedAt: m.CreatedAt,
+		RotatedAt: m.RotatedAt,
+		RevokedAt: m.RevokedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type AuditEntry struct {
+	Id             string    `db:"id"`
+	SubscriptionID string    `db:"subscription_id"`
+	UserID         string    `db:"user_id"`
+	Action         string    `db:"action"`
+	Actor          string    `db:"actor"`
+	OnBehalfOf     *string   `db:"on_behalf_of"`
+	RequestID      *string   `db:"request_id"`
+	Before         []byte    `db:"before_state"`
+	After          []byte    `db:"after_state"`
+	Changes        []byte    `db:"changes"`
+	OccurredAt     time.Time `db:"occurred_at"`
+}
+
+// AuditFilter selects audit entries; From is inclusive and To exclusive.
+type AuditFilter struct {
+	SubscriptionID *string
+	UserID         *string
+	Actor          *string
+	Action         *string
+	From           *time.Time
+	To             *time.Time
+}
+
+func AuditEntryModelToDomain(m *AuditEntry) *domain.AuditEntry {
+	return &domain.AuditEntry{
+		Id:             m.Id,
+		SubscriptionID: m.SubscriptionID,
+		UserID:         m.UserID,
+		Action:         domain.AuditAction(m.Action),
+		Actor:          m.Actor,
+		OnBehalfOf:     m.OnBehalfOf,
+		RequestID:      m.RequestID,
+		Before:         m.Before,
+		After:          m.After,
+		Changes:        m.Changes,
+		OccurredAt:     m.OccurredAt,
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type OutboxEvent struct {
+	Id             string     `db:"id"`
+	EventType      string     `db:"event_type"`
+	SubscriptionID string     `db:"subscription_id"`
+	Payload        []byte     `db:"payload"`
+	OccurredAt     time.Time  `db:"occurred_at"`
+	Attempts       int        `db:"attempts"`
+	PublishedAt    *time.Time `db:"published_at"`
+	ParkedAt       *time.Time `db:"parked_at"`
+}
+
+func OutboxEventModelToDomain(m *OutboxEvent) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		Id:             m.Id,
+		Type:           domain.EventType(m.EventType),
+		SubscriptionID: m.SubscriptionID,
+		Payload:        m.Payload,
+		OccurredAt:     m.OccurredAt,
+		Attempts:       m.Attempts,
+		PublishedAt:    m.PublishedAt,
+		ParkedAt:       m.ParkedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type Service struct {
+	Id           string     `db:"id"`
+	Name         string     `db:"name"`
+	Aliases      StringList `db:"aliases"`
+	Category     *string    `db:"category"`
+	DefaultPrice *int       `db:"default_price"`
+	LogoURL      *string    `db:"logo_url"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// StringList scans a text array selected as a JSON array.
+type StringList []string
+
+func (l *StringList) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("models.StringList: unsupported type %T", src)
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+func ServiceModelToDomain(m *Service) *domain.Service {
+	aliases := make([]string, 0, len(m.Aliases))
+	aliases = append(aliases, m.Aliases...)
+	return &domain.Service{
+		Id:           m.Id,
+		Name:         m.Name,
+		Aliases:      aliases,
+		Category:     m.Category,
+		DefaultPrice: m.DefaultPrice,
+		LogoURL:      m.LogoURL,
+		CreatedAt:    m.CreatedAt,
+	}
+}
@@ -7,44 +7,220 @@ import (
 )
 
 type Subscription struct {
-	Id          string     `db:"id"`
-	ServiceName string     `db:"service_name"`
-	Price       int        `db:"price"`
-	UserID      string     `db:"user_id"`
-	StartDate   time.Time  `db:"start_date"`
-	EndDate     *time.Time `db:"end_date"`
+	Id            string               `db:"id"`
+	ServiceName   string               `db:"service_name"`
+	ServiceID     *string              `db:"service_id"`
+	Price         int                  `db:"price"`
+	Currency      string               `db:"currency"`
+	BillingPeriod string               `db:"billing_period"`
+	UserID        string               `db:"user_id"`
+	StartDate     time.Time            `db:"start_date"`
+	EndDate       *time.Time           `db:"end_date"`
+	TrialEndDate  *time.Time           `db:"trial_end_date"`
+	CreatedAt     time.Time            `db:"created_at"`
+	Status        string               `db:"status"`
+	CancelledAt   *time.Time           `db:"cancelled_at"`
+	DeletedAt     *time.Time           `db:"deleted_at"`
+	Version       int64                `db:"version"`
+	Prices        []SubscriptionPrice  `db:"-"`
+	Pauses        []SubscriptionPause  `db:"-"`
+	Tags          []string             `db:"-"`
+	Members       []SubscriptionMember `db:"-"`
+}
+
+type SubscriptionPrice struct {
+	SubscriptionID string    `db:"subscription_id"`
+	Price          int       `db:"price"`
+	EffectiveFrom  time.Time `db:"effective_from"`
+}
+
+type SubscriptionMember struct {
+	SubscriptionID string `db:"subscription_id"`
+	UserID         string `db:"user_id"`
+	Weight         *int   `db:"weight"`
+	FixedAmount    *int   `db:"fixed_amount"`
+}
+
+type SubscriptionTag struct {
+	SubscriptionID string `db:"subscription_id"`
+	Name           string `db:"name"`
+}
+
+type SubscriptionPause struct {
+	SubscriptionID string     `db:"subscription_id"`
+	StartDate      time.Time  `db:"start_date"`
+	EndDate        *time.Time `db:"end_date"`
 }
 
 type SubscriptionUpdate struct {
-	ServiceName *string    `db:"service_name"`
-	Price       *int       `db:"price"`
-	UserID      *string    `db:"user_id"`
-	StartDate   *time.Time `db:"start_date"`
-	EndDate     *time.Time `db:"end_date"`
+	ServiceName *string `db:"service_name"`
+	// ServiceID is the catalog service of ServiceName, nil when the service
+	// is not in the catalog. It is written whenever ServiceName is set.
+	ServiceID     *string    `db:"service_id"`
+	Price         *int       `db:"price"`
+	Currency      *string    `db:"currency"`
+	BillingPeriod *string    `db:"billing_period"`
+	UserID        *string    `db:"user_id"`
+	StartDate     *time.Time `db:"start_date"`
+	EndDate       *time.Time `db:"end_date"`
+	TrialEndDate  *time.Time `db:"trial_end_date"`
+	// PriceEffectiveFrom is the date from which Price applies.
+	PriceEffectiveFrom *time.Time `db:"-"`
+	// Tags replaces all tags of the subscription when set.
+	Tags *[]string `db:"-"`
+	// Version is the version the subscription is expected to have, if set.
+	Version *int64 `db:"-"`
+}
+
+// SubscriptionBatchUpdate is the update of a single subscription within a
+// batch.
+type SubscriptionBatchUpdate struct {
+	Id     string
+	Update SubscriptionUpdate
 }
 
+type SubscriptionFilter struct {
+	UserID            *string
+	ServiceName       *string
+	ServiceNamePrefix *string
+	MinPrice          *int
+	MaxPrice          *int
+	ActiveAt          *time.Time
+	StartDateFrom     *time.Time
+	StartDateTo       *time.Time
+	EndDateFrom       *time.Time
+	EndDateTo         *time.Time
+	NoEndDate         *bool
+	TrialEndFrom      *time.Time
+	TrialEndTo        *time.Time
+	Tag               *string
+	// ActiveFrom and ActiveTo select subscriptions active in any month
+	// between them.
+	ActiveFrom *time.Time
+	ActiveTo   *time.Time
+	// IncludeDeleted selects deleted subscriptions as well.
+	IncludeDeleted bool
+}
+
+// SubscriptionKey is the keyset pagination key of a subscription.
+type SubscriptionKey struct {
+	CreatedAt time.Time
+	Id        string
+}
+
+type UserSubscriptionsCount struct {
+	Total  int `db:"total"`
+	Active int `db:"active"`
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// GetTotalPriceFilter selects subscriptions paid by UserID, or paid by or
+// shared with Member.
 type GetTotalPriceFilter struct {
 	UserID      *string    `db:"user_id"`
 	ServiceName *string    `db:"service_name"`
+	Tag         *string    `db:"-"`
+	Member      *string    `db:"-"`
 	StartDate   *time.Time `db:"start_date"`
 	EndDate     *time.Time `db:"end_date"`
 }
 
 func SubscriptionDomainToModel(d *domain.Subscription) *Subscription {
+	prices := make([]SubscriptionPrice, 0, len(d.Prices))
+	for _, p := range d.Prices {
+		prices = append(prices, SubscriptionPrice{
+			SubscriptionID: d.Id,
+			Price:          p.Price,
+			EffectiveFrom:  p.EffectiveFrom,
+		})
+	}
+	pauses := make([]SubscriptionPause, 0, len(d.Pauses))
+	for _, p := range d.Pauses {
+		pauses = append(pauses, SubscriptionPause{
+			SubscriptionID: d.Id,
+			StartDate:      p.StartDate,
+			EndDate:        p.EndDate,
+		})
+	}
+	members := make([]SubscriptionMember, 0, len(d.Members))
+	for _, m := range d.Members {
+		members = append(members, SubscriptionMember{
+			SubscriptionID: d.Id,
+			UserID:         m.UserID,
+			Weight:         m.Weight,
+			FixedAmount:    m.FixedAmount,
+		})
+	}
 	return &Subscription{
-		Id:          d.Id,
-		ServiceName: d.ServiceName,
-		Price:       d.Price,
-		UserID:      d.UserID,
-		StartDate:   d.StartDate,
+		Id:            d.Id,
+		ServiceName:   d.ServiceName,
+		ServiceID:     d.ServiceID,
+		Price:         d.Price,
+		Currency:      d.Currency,
+		BillingPeriod: string(d.BillingPeriod),
+		UserID:        d.UserID,
+		StartDate:     d.StartDate,
+		EndDate:       d.EndDate,
+		TrialEndDate:  d.TrialEndDate,
+		CreatedAt:     d.CreatedAt,
+		Status:        string(d.Status),
+		CancelledAt:   d.CancelledAt,
+		DeletedAt:     d.DeletedAt,
+		Version:       d.Version,
+		Prices:        prices,
+		Pauses:        pauses,
+		Tags:          d.Tags,
+		Members:       members,
 	}
 }
 func SubscriptionModelToDomain(m *Subscription) *domain.Subscription {
+	prices := make([]domain.PriceChange, 0, len(m.Prices))
+	for _, p := range m.Prices {
+		prices = append(prices, domain.PriceChange{
+			Price:         p.Price,
+			EffectiveFrom: p.EffectiveFrom,
+		})
+	}
+	pauses := make([]domain.Pause, 0, len(m.Pauses))
+	for _, p := range m.Pauses {
+		pauses = append(pauses, domain.Pause{
+			StartDate: p.StartDate,
+			EndDate:   p.EndDate,
+		})
+	}
+	members := make([]domain.Member, 0, len(m.Members))
+	for _, member := range m.Members {
+		members = append(members, domain.Member{
+			UserID:      member.UserID,
+			Weight:      member.Weight,
+			FixedAmount: member.FixedAmount,
+		})
+	}
+	tags := make([]string, 0, len(m.Tags))
+	tags = append(tags, m.Tags...)
 	return &domain.Subscription{
-		Id:          m.Id,
-		ServiceName: m.ServiceName,
-		Price:       m.Price,
-		UserID:      m.UserID,
-		StartDate:   m.StartDate,
+		Id:            m.Id,
+		ServiceName:   m.ServiceName,
+		ServiceID:     m.ServiceID,
+		Price:         m.Price,
+		Currency:      m.Currency,
+		BillingPeriod: domain.BillingPeriod(m.BillingPeriod),
+		UserID:        m.UserID,
+		StartDate:     m.StartDate,
+		EndDate:       m.EndDate,
+		TrialEndDate:  m.TrialEndDate,
+		CreatedAt:     m.CreatedAt,
+		Status:        domain.Status(m.Status),
+		CancelledAt:   m.CancelledAt,
+		DeletedAt:     m.DeletedAt,
+		Version:       m.Version,
+		Prices:        prices,
+		Pauses:        pauses,
+		Tags:          tags,
+		Members:       members,
 	}
 }
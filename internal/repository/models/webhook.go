@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+type WebhookEndpoint struct {
+	Id         string    `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes string    `db:"event_types"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type WebhookDelivery struct {
+	Id             string     `db:"id"`
+	EndpointID     string     `db:"endpoint_id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      *string    `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	EndpointID string
+	Status     *string
+}
+
+func WebhookEndpointModelToDomain(m *WebhookEndpoint) *domain.WebhookEndpoint {
+	fields := strings.Fields(m.EventTypes)
+	eventTypes := make([]domain.EventType, 0, len(fields))
+	for _, t := range fields {
+		eventTypes = append(eventTypes, domain.EventType(t))
+	}
+	return &domain.WebhookEndpoint{
+		Id:         m.Id,
+		URL:        m.URL,
+		Secret:     m.Secret,
+		EventTypes: eventTypes,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func WebhookDeliveryModelToDomain(m *WebhookDelivery) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		Id:             m.Id,
+		EndpointID:     m.EndpointID,
+		EventID:        m.EventID,
+		EventType:      domain.EventType(m.EventType),
+		Payload:        m.Payload,
+		Status:         domain.DeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      m.LastError,
+		CreatedAt:      m.CreatedAt,
+		DeliveredAt:    m.DeliveredAt,
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// NotificationRepo records the reminders that have been sent.
+type NotificationRepo struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepo {
+	return &NotificationRepo{
+		db: db,
+	}
+}
+
+// Claim records the reminder as sent before it is sent, so that concurrent
+// schedulers never send it twice. It reports false when the reminder has
+// been claimed before.
+func (r *NotificationRepo) Claim(ctx context.Context, reminder *domain.Reminder, sentAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO sent_notifications (subscription_id, kind, due_date, user_id, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+	`, reminder.SubscriptionID, string(reminder.Kind), reminder.Date, reminder.UserID, sentAt)
+	if err != nil {
+		return false, fmt.Errorf("notificationRepo.Claim: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("notificationRepo.Claim: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// Release removes the claim of a reminder that failed to be sent, so that it
+// is retried.
+func (r *NotificationRepo) Release(ctx context.Context, reminder *domain.Reminder) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM sent_notifications WHERE subscription_id = $1 AND kind = $2 AND due_date = $3
+	`, reminder.SubscriptionID, string(reminder.Kind), reminder.Date)
+	if err != nil {
+		return fmt.Errorf("notificationRepo.Release: %w", err)
+	}
+	return nil
+}
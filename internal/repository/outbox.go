@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/event"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+const outboxEventColumns = "id, event_type, subscription_id, payload, occurred_at, attempts, published_at, parked_at"
+
+// OutboxRepo relays the events that subscription writes record in the
+// outbox within their own transactions, so that an event is stored if and
+// only if its change is.
+type OutboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepo {
+	return &OutboxRepo{
+		db: db,
+	}
+}
+
+// Add records events that are not raised by a subscription write. Events
+// recorded before are skipped, so events with deterministic IDs can be added
+// repeatedly.
+func (r *OutboxRepo) Add(ctx context.Context, events []*domain.Event) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("outboxRepo.Add: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordEvents(ctx, tx, events...); err != nil {
+		return fmt.Errorf("outboxRepo.Add: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("outboxRepo.Add: %w", err)
+	}
+	return nil
+}
+
+// Relay locks up to limit unpublished events, oldest first, and passes them
+// to publish in order. Events locked by a concurrent relay are skipped. An
+// event is marked as published once publish returns; a failure is recorded
+// on its event and stops the batch, leaving it and the events after it for
+// the next run, unless the event has now failed maxAttempts times. It is
+// then parked and the batch goes on without it. A crash before the commit
+// publishes the batch again, so every event that is not parked is published
+// at least once. Relay returns the number of events published.
+func (r *OutboxRepo) Relay(ctx context.Context, limit, maxAttempts int, publish func(*domain.OutboxEvent) error) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("outboxRepo.Relay: %w", err)
+	}
+	defer tx.Rollback()
+
+	events := make([]*models.OutboxEvent, 0)
+	query := `
+		SELECT ` + outboxEventColumns + ` FROM outbox_events
+		WHERE published_at IS NULL AND parked_at IS NULL
+		ORDER BY occurred_at, id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &events, query, limit); err != nil {
+		return 0, fmt.Errorf("outboxRepo.Relay: %w", err)
+	}
+
+	published := 0
+	var publishErrs []error
+	for _, e := range events {
+		outboxEvent := models.OutboxEventModelToDomain(e)
+		if publishErr := publish(outboxEvent); publishErr != nil {
+			publishErrs = append(publishErrs, publishErr)
+			outboxEvent.Fail(time.Now().UTC(), maxAttempts)
+			_, err := tx.ExecContext(ctx,
+				"UPDATE outbox_events SET attempts = $1, last_error = $2, parked_at = $3 WHERE id = $4",
+				outboxEvent.Attempts, publishErr.Error(), outboxEvent.ParkedAt, e.Id)
+			if err != nil {
+				return 0, fmt.Errorf("outboxRepo.Relay: %w", err)
+			}
+			if outboxEvent.ParkedAt == nil {
+				break
+			}
+			continue
+		}
+		_, err := tx.ExecContext(ctx,
+			"UPDATE outbox_events SET attempts = attempts + 1, last_error = NULL, published_at = $1 WHERE id = $2", time.Now().UTC(), e.Id)
+		if err != nil {
+			return 0, fmt.Errorf("outboxRepo.Relay: %w", err)
+		}
+		published++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("outboxRepo.Relay: %w", err)
+	}
+	if len(publishErrs) > 0 {
+		return published, fmt.Errorf("outboxRepo.Relay: %w", errors.Join(publishErrs...))
+	}
+	return published, nil
+}
+
+// Prune deletes the events published before the given time and returns how
+// many were deleted.
+func (r *OutboxRepo) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM outbox_events WHERE published_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("outboxRepo.Prune: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows, nil
+}
+
+// recordEvents stores the events in the outbox, skipping those recorded
+// before.
+func recordEvents(ctx context.Context, e sqlx.ExecerContext, events ...*domain.Event) error {
+	for _, ev := range events {
+		outboxEvent, err := event.Encode(ev)
+		if err != nil {
+			return err
+		}
+		_, err = e.ExecContext(ctx, `
+			INSERT INTO outbox_events (id, event_type, subscription_id, payload, occurred_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO NOTHING
+		`, outboxEvent.Id, string(outboxEvent.Type), outboxEvent.SubscriptionID, string(outboxEvent.Payload), outboxEvent.OccurredAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// beginChange locks the subscription for a change within tx, bumping its
+// version, and returns its state before the change. When expected is set, it
+// returns ErrConflict unless the stored version equals it.
+func beginChange(ctx context.Context, tx *sqlx.Tx, id string, expected *int64) (*domain.Subscription, error) {
+	if err := bumpVersion(ctx, tx, id, expected); err != nil {
+		return nil, err
+	}
+	return getSubscription(ctx, tx, id)
+}
+
+// recordChange stores an update event with the state of the subscription as
+// written within tx and appends the update from before, as returned by
+// beginChange, to the audit log.
+func recordChange(ctx context.Context, tx *sqlx.Tx, before *domain.Subscription) error {
+	after, err := getSubscription(ctx, tx, before.Id)
+	if err != nil {
+		return err
+	}
+	if err := recordEvents(ctx, tx, newEvent(domain.EventSubscriptionUpdated, after)); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, domain.AuditActionUpdate, before, after)
+}
+
+func newEvent(eventType domain.EventType, subscription *domain.Subscription) *domain.Event {
+	return domain.NewSubscriptionEvent(uuid.NewString(), eventType, subscription, time.Now().UTC())
+}
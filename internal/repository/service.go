@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// serviceColumns selects aliases as a JSON array.
+const serviceColumns = "id, name, array_to_json(aliases) AS aliases, category, default_price, logo_url, created_at"
+
+type ServiceRepo struct {
+	db *sqlx.DB
+}
+
+func NewServiceRepository(db *sqlx.DB) *ServiceRepo {
+	return &ServiceRepo{
+		db: db,
+	}
+}
+
+func (r *ServiceRepo) Create(ctx context.Context, input *domain.Service) error {
+	aliases, err := json.Marshal(input.Aliases)
+	if err != nil {
+		return fmt.Errorf("serviceRepo.Create:%w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+    INSERT INTO services (id, name, aliases, category, default_price, logo_url, created_at)
+    VALUES ($1, $2, ARRAY(SELECT json_array_elements_text($3::json)), $4, $5, $6, $7)
+`, input.Id, input.Name, string(aliases), input.Category, input.DefaultPrice, input.LogoURL, input.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("serviceRepo.Create:%w", err)
+	}
+	return nil
+}
+
+func (r *ServiceRepo) GetAll(ctx context.Context) ([]*domain.Service, error) {
+	services := make([]*models.Service, 0)
+	query := "SELECT " + serviceColumns + " FROM services ORDER BY name, id"
+	if err := r.db.SelectContext(ctx, &services, query); err != nil {
+		return nil, fmt.Errorf("serviceRepo.GetAll: %w", err)
+	}
+	servicesDomain := make([]*domain.Service, 0, len(services))
+	for _, s := range services {
+		servicesDomain = append(servicesDomain, models.ServiceModelToDomain(s))
+	}
+	return servicesDomain, nil
+}
+
+func (r *ServiceRepo) GetById(ctx context.Context, id string) (*domain.Service, error) {
+	var service models.Service
+	query := "SELECT " + serviceColumns + " FROM services WHERE id = $1"
+	if err := r.db.GetContext(ctx, &service, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("serviceRepo.GetById: %w", err)
+	}
+	return models.ServiceModelToDomain(&service), nil
+}
+
+// FindByName returns the service whose canonical name or one of the aliases
+// matches name, ignoring case.
+func (r *ServiceRepo) FindByName(ctx context.Context, name string) (*domain.Service, error) {
+	var service models.Service
+	query := "SELECT " + serviceColumns + ` FROM services
+		WHERE LOWER(name) = LOWER($1)
+			OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE LOWER(alias) = LOWER($1))
+		ORDER BY LOWER(name) = LOWER($1) DESC, id
+		LIMIT 1`
+	if err := r.db.GetContext(ctx, &service, query, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("serviceRepo.FindByName: %w", err)
+	}
+	return models.ServiceModelToDomain(&service), nil
+}
+
+func (r *ServiceRepo) Update(ctx context.Context, input *domain.Service) error {
+	aliases, err := json.Marshal(input.Aliases)
+	if err != nil {
+		return fmt.Errorf("serviceRepo.Update: %w", err)
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE services
+		SET name = $1, aliases = ARRAY(SELECT json_array_elements_text($2::json)), category = $3, default_price = $4, logo_url = $5
+		WHERE id = $6
+	`, input.Name, string(aliases), input.Category, input.DefaultPrice, input.LogoURL, input.Id)
+	if err != nil {
+		return fmt.Errorf("serviceRepo.Update: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *ServiceRepo) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM services WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("serviceRepo.Delete:%w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
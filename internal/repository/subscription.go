@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/scmbr/subscription-aggregator/internal/domain"
@@ -22,20 +23,96 @@ func NewSubscriptionRepository(db *sqlx.DB) *SubscriptionRepo {
 	}
 }
 func (r *SubscriptionRepo) Create(ctx context.Context, input *domain.Subscription) error {
-	_, err := r.db.ExecContext(ctx, `
-    INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date)
-    VALUES ($1, $2, $3, $4, $5, $6)
-`, input.Id, input.ServiceName, input.Price, input.UserID, input.StartDate, input.EndDate)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("subscriptionRepo.Create:%w", err)
 	}
+	defer tx.Rollback()
+
+	if err := createSubscription(ctx, tx, input); err != nil {
+		return fmt.Errorf("subscriptionRepo.Create:%w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.Create:%w", err)
+	}
 	return nil
 }
-func (r *SubscriptionRepo) GetAll(ctx context.Context, limit, offset int) ([]*domain.Subscription, int, error) {
-	query := `SELECT id, service_name, price, user_id, start_date, end_date
-              FROM subscriptions`
 
-	args := []interface{}{}
+// createSubscription inserts the subscription with its price history and
+// tags and records the creation in the outbox and the audit log.
+func createSubscription(ctx context.Context, tx *sqlx.Tx, input *domain.Subscription) error {
+	_, err := tx.ExecContext(ctx, `
+    INSERT INTO subscriptions (id, service_name, service_id, price, currency, billing_period, user_id, start_date, end_date, trial_end_date, created_at, status, version)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+`, input.Id, input.ServiceName, input.ServiceID, input.Price, input.Currency, input.BillingPeriod, input.UserID, input.StartDate, input.EndDate, input.TrialEndDate, input.CreatedAt, input.Status, input.Version)
+	if err != nil {
+		return err
+	}
+	for _, p := range models.SubscriptionDomainToModel(input).Prices {
+		if err := upsertPrice(ctx, tx, p); err != nil {
+			return err
+		}
+	}
+	if err := replaceTags(ctx, tx, input.Id, input.Tags); err != nil {
+		return err
+	}
+	if err := recordEvents(ctx, tx, newEvent(domain.EventSubscriptionCreated, input)); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, domain.AuditActionCreate, nil, input)
+}
+
+const subscriptionColumns = "id, service_name, service_id, price, currency, billing_period, user_id, start_date, end_date, trial_end_date, created_at, status, cancelled_at, deleted_at, version"
+
+// notDeletedCond excludes deleted subscriptions, which every query does
+// unless asked otherwise.
+const notDeletedCond = "deleted_at IS NULL"
+
+// currentPriceExpr evaluates to the price of a subscription in effect now.
+const currentPriceExpr = `COALESCE((
+	SELECT sp.price FROM subscription_prices sp
+	WHERE sp.subscription_id = subscriptions.id AND sp.effective_from <= NOW()
+	ORDER BY sp.effective_from DESC LIMIT 1
+), subscriptions.price)`
+
+// sortColumns whitelists the fields subscriptions can be sorted by.
+var sortColumns = map[string]string{
+	"service_name": "service_name",
+	"price":        currentPriceExpr,
+	"user_id":      "user_id",
+	"start_date":   "start_date",
+	"end_date":     "end_date",
+}
+
+func (r *SubscriptionRepo) GetAll(
+	ctx context.Context,
+	filter models.SubscriptionFilter,
+	sort []models.SortField,
+	limit, offset int,
+) ([]*domain.Subscription, int, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions"
+	countQuery := "SELECT COUNT(*) FROM subscriptions"
+
+	where, args := subscriptionFilterToWhere(filter)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+		countQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+	countArgs := append([]interface{}{}, args...)
+
+	order := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		column, ok := sortColumns[s.Field]
+		if !ok {
+			return nil, 0, fmt.Errorf("subscriptionRepo.GetAll: unknown sort field %q", s.Field)
+		}
+		if s.Desc {
+			column += " DESC NULLS LAST"
+		}
+		order = append(order, column)
+	}
+	order = append(order, "id")
+	query += " ORDER BY " + strings.Join(order, ", ")
 
 	if limit > 0 {
 		query += " LIMIT ?"
@@ -46,8 +123,6 @@ func (r *SubscriptionRepo) GetAll(ctx context.Context, limit, offset int) ([]*do
 		args = append(args, offset)
 	}
 
-	countQuery := "SELECT COUNT(*) FROM subscriptions"
-
 	query = sqlx.Rebind(sqlx.DOLLAR, query)
 	countQuery = sqlx.Rebind(sqlx.DOLLAR, countQuery)
 
@@ -57,7 +132,11 @@ func (r *SubscriptionRepo) GetAll(ctx context.Context, limit, offset int) ([]*do
 	}
 
 	var count int
-	if err := r.db.GetContext(ctx, &count, countQuery); err != nil {
+	if err := r.db.GetContext(ctx, &count, countQuery, countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("subscriptionRepo.GetAll: %w", err)
+	}
+
+	if err := loadDetails(ctx, r.db, subscriptions); err != nil {
 		return nil, 0, fmt.Errorf("subscriptionRepo.GetAll: %w", err)
 	}
 
@@ -69,33 +148,251 @@ func (r *SubscriptionRepo) GetAll(ctx context.Context, limit, offset int) ([]*do
 	return subscriptionsDomain, count, nil
 }
 
+// GetPage returns up to limit subscriptions ordered by (created_at, id) that
+// come strictly after the after key, or from the beginning when it is nil.
+// Unlike GetAll it does not count the matching subscriptions, so that a page
+// costs the same however deep it is.
+func (r *SubscriptionRepo) GetPage(
+	ctx context.Context,
+	filter models.SubscriptionFilter,
+	after *models.SubscriptionKey,
+	limit int,
+) ([]*domain.Subscription, error) {
+	subscriptions, err := selectPage(ctx, r.db, filter, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetPage: %w", err)
+	}
+
+	subscriptionsDomain := make([]*domain.Subscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		subscriptionsDomain = append(subscriptionsDomain, models.SubscriptionModelToDomain(s))
+	}
+
+	return subscriptionsDomain, nil
+}
+
+// selectPage loads a page of subscriptions with their details, see GetPage.
+func selectPage(
+	ctx context.Context,
+	q sqlx.QueryerContext,
+	filter models.SubscriptionFilter,
+	after *models.SubscriptionKey,
+	limit int,
+) ([]*models.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions"
+
+	where, args := subscriptionFilterToWhere(filter)
+	if after != nil {
+		where = append(where, "(created_at, id) > (?, ?)")
+		args = append(args, after.CreatedAt, after.Id)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at, id LIMIT ?"
+	args = append(args, limit)
+
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	subscriptions := make([]*models.Subscription, 0)
+	if err := sqlx.SelectContext(ctx, q, &subscriptions, query, args...); err != nil {
+		return nil, err
+	}
+	if err := loadDetails(ctx, q, subscriptions); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// subscriptionFilterToWhere builds WHERE conditions with "?" placeholders.
+func subscriptionFilterToWhere(filter models.SubscriptionFilter) ([]string, []interface{}) {
+	where := []string{}
+	args := []interface{}{}
+
+	if !filter.IncludeDeleted {
+		where = append(where, notDeletedCond)
+	}
+	if filter.UserID != nil {
+		where = append(where, "user_id = ?")
+		args = append(args, *filter.UserID)
+	}
+	if filter.ServiceName != nil {
+		where = append(where, "service_name = ?")
+		args = append(args, *filter.ServiceName)
+	}
+	if filter.ServiceNamePrefix != nil {
+		where = append(where, `service_name ILIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(*filter.ServiceNamePrefix)+"%")
+	}
+	if filter.MinPrice != nil {
+		where = append(where, currentPriceExpr+" >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where = append(where, currentPriceExpr+" <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.ActiveAt != nil {
+		where = append(where, "start_date <= ?", "(end_date >= ? OR end_date IS NULL)")
+		args = append(args, *filter.ActiveAt, *filter.ActiveAt)
+	}
+	if filter.ActiveFrom != nil {
+		where = append(where, "(end_date >= ? OR end_date IS NULL)")
+		args = append(args, *filter.ActiveFrom)
+	}
+	if filter.ActiveTo != nil {
+		where = append(where, "start_date <= ?")
+		args = append(args, *filter.ActiveTo)
+	}
+	if filter.StartDateFrom != nil {
+		where = append(where, "start_date >= ?")
+		args = append(args, *filter.StartDateFrom)
+	}
+	if filter.StartDateTo != nil {
+		where = append(where, "start_date <= ?")
+		args = append(args, *filter.StartDateTo)
+	}
+	if filter.EndDateFrom != nil {
+		where = append(where, "end_date >= ?")
+		args = append(args, *filter.EndDateFrom)
+	}
+	if filter.EndDateTo != nil {
+		where = append(where, "end_date <= ?")
+		args = append(args, *filter.EndDateTo)
+	}
+	if filter.TrialEndFrom != nil {
+		where = append(where, "trial_end_date >= ?")
+		args = append(args, *filter.TrialEndFrom)
+	}
+	if filter.TrialEndTo != nil {
+		where = append(where, "trial_end_date < ?")
+		args = append(args, *filter.TrialEndTo)
+	}
+	if filter.Tag != nil {
+		where = append(where, hasTagCond)
+		args = append(args, *filter.Tag)
+	}
+	if filter.NoEndDate != nil {
+		if *filter.NoEndDate {
+			where = append(where, "end_date IS NULL")
+		} else {
+			where = append(where, "end_date IS NOT NULL")
+		}
+	}
+
+	return where, args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *SubscriptionRepo) GetById(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := getSubscription(ctx, r.db, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("subscriptionRepo.GetById: %w", err)
+	}
+	return subscription, nil
+}
+
+// GetDeletedById returns a deleted subscription that has not been purged
+// yet.
+func (r *SubscriptionRepo) GetDeletedById(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := selectSubscription(ctx, r.db, id, true)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("subscriptionRepo.GetDeletedById: %w", err)
+	}
+	return subscription, nil
+}
+
+// getSubscription loads the subscription with its details. It returns
+// ErrNotFound when the subscription does not exist or is deleted.
+func getSubscription(ctx context.Context, q sqlx.QueryerContext, id string) (*domain.Subscription, error) {
+	return selectSubscription(ctx, q, id, false)
+}
+
+// selectSubscription loads either a live or a deleted subscription with its
+// details.
+func selectSubscription(ctx context.Context, q sqlx.QueryerContext, id string, deleted bool) (*domain.Subscription, error) {
+	cond := notDeletedCond
+	if deleted {
+		cond = "deleted_at IS NOT NULL"
+	}
 	var subscription models.Subscription
-	query := "SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions WHERE id = $1"
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE id = $1 AND " + cond
 
-	if err := r.db.GetContext(ctx, &subscription, query, id); err != nil {
+	if err := sqlx.GetContext(ctx, q, &subscription, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
 
+	if err := loadDetails(ctx, q, []*models.Subscription{&subscription}); err != nil {
+		return nil, err
+	}
+
 	return models.SubscriptionModelToDomain(&subscription), nil
 }
 
 func (r *SubscriptionRepo) Update(ctx context.Context, id string, input models.SubscriptionUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.Update: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateSubscription(ctx, tx, id, input); err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+			return err
+		}
+		return fmt.Errorf("subscriptionRepo.Update: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.Update: %w", err)
+	}
+	return nil
+}
+
+// updateSubscription applies the update to the subscription and records it
+// in the outbox and the audit log. It returns ErrNotFound when the
+// subscription does not exist and ErrConflict when its version is not
+// input.Version.
+func updateSubscription(ctx context.Context, tx *sqlx.Tx, id string, input models.SubscriptionUpdate) error {
+	before, err := beginChange(ctx, tx, id, input.Version)
+	if err != nil {
+		return err
+	}
+
 	set := []string{}
 	args := []interface{}{}
 	idx := 1
 
 	if input.ServiceName != nil {
-		set = append(set, fmt.Sprintf("service_name = $%d", idx))
-		args = append(args, *input.ServiceName)
+		set = append(set, fmt.Sprintf("service_name = $%d, service_id = $%d", idx, idx+1))
+		args = append(args, *input.ServiceName, input.ServiceID)
+		idx += 2
+	}
+	if input.Currency != nil {
+		set = append(set, fmt.Sprintf("currency = $%d", idx))
+		args = append(args, *input.Currency)
 		idx++
 	}
-	if input.Price != nil {
-		set = append(set, fmt.Sprintf("price = $%d", idx))
-		args = append(args, *input.Price)
+	if input.BillingPeriod != nil {
+		set = append(set, fmt.Sprintf("billing_period = $%d", idx))
+		args = append(args, *input.BillingPeriod)
+		idx++
+	}
+	if input.UserID != nil {
+		set = append(set, fmt.Sprintf("user_id = $%d", idx))
+		args = append(args, *input.UserID)
 		idx++
 	}
 	if input.StartDate != nil {
@@ -103,43 +400,137 @@ func (r *SubscriptionRepo) Update(ctx context.Context, id string, input models.S
 		args = append(args, *input.StartDate)
 		idx++
 	}
+	if input.EndDate != nil {
+		set = append(set, fmt.Sprintf("end_date = $%d", idx))
+		args = append(args, *input.EndDate)
+		idx++
+	}
+	if input.TrialEndDate != nil {
+		set = append(set, fmt.Sprintf("trial_end_date = $%d", idx))
+		args = append(args, *input.TrialEndDate)
+		idx++
+	}
+
+	if len(set) > 0 {
+		args = append(args, id)
+		query := fmt.Sprintf("UPDATE subscriptions SET %s WHERE id = $%d AND %s", strings.Join(set, ", "), idx, notDeletedCond)
 
-	if len(set) == 0 {
-		return nil
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if rows, _ := res.RowsAffected(); rows == 0 {
+			return ErrNotFound
+		}
 	}
 
-	args = append(args, id)
-	query := fmt.Sprintf("UPDATE subscriptions SET %s WHERE id = $%d", strings.Join(set, ", "), idx)
+	if input.Price != nil {
+		err := upsertPrice(ctx, tx, models.SubscriptionPrice{
+			SubscriptionID: id,
+			Price:          *input.Price,
+			EffectiveFrom:  *input.PriceEffectiveFrom,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if input.Tags != nil {
+		if err := replaceTags(ctx, tx, id, *input.Tags); err != nil {
+			return err
+		}
+	}
+	return recordChange(ctx, tx, before)
+}
 
-	res, err := r.db.ExecContext(ctx, query, args...)
-	if rows, _ := res.RowsAffected(); rows == 0 {
-		return ErrNotFound
+// Delete deletes the subscription. When version is set, it returns
+// ErrConflict unless the stored version equals it.
+func (r *SubscriptionRepo) Delete(ctx context.Context, id string, version *int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.Delete:%w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteSubscription(ctx, tx, id, version); err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+			return err
+		}
+		return fmt.Errorf("subscriptionRepo.Delete:%w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.Delete:%w", err)
+	}
+	return nil
+}
+
+// deleteSubscription marks the subscription as deleted and records its last
+// state in the outbox and the audit log. It returns ErrNotFound when the
+// subscription does not exist or is already deleted and ErrConflict when its
+// version is not the expected one.
+func deleteSubscription(ctx context.Context, tx *sqlx.Tx, id string, version *int64) error {
+	before, err := beginChange(ctx, tx, id, version)
+	if err != nil {
+		return err
+	}
+	if err := recordEvents(ctx, tx, newEvent(domain.EventSubscriptionDeleted, before)); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, domain.AuditActionDelete, before, nil); err != nil {
+		return err
 	}
+	_, err = tx.ExecContext(ctx, "UPDATE subscriptions SET deleted_at = $1 WHERE id = $2", time.Now().UTC(), id)
 	return err
 }
 
-func (r *SubscriptionRepo) Delete(ctx context.Context, id string) error {
-	res, err := r.db.ExecContext(ctx, "DELETE FROM subscriptions WHERE id = $1", id)
+// Restore undoes the deletion of a subscription that has not been purged
+// yet. It returns ErrNotFound when there is no such subscription.
+func (r *SubscriptionRepo) Restore(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("subscriptionRepo.Delete:%w", err)
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
 	}
+	defer tx.Rollback()
 
+	res, err := tx.ExecContext(ctx, "UPDATE subscriptions SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
+	}
 	if rows, _ := res.RowsAffected(); rows == 0 {
 		return ErrNotFound
 	}
-
+	restored, err := getSubscription(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
+	}
+	if err := recordEvents(ctx, tx, newEvent(domain.EventSubscriptionRestored, restored)); err != nil {
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
+	}
+	if err := recordAudit(ctx, tx, domain.AuditActionRestore, nil, restored); err != nil {
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.Restore: %w", err)
+	}
 	return nil
 }
-func (r *SubscriptionRepo) GetTotalPrice(
+
+// Purge removes the subscriptions deleted before the given time for good,
+// along with their price history, pauses, tags and members.
+func (r *SubscriptionRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM subscriptions WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("subscriptionRepo.Purge: %w", err)
+	}
+	purged, _ := res.RowsAffected()
+	return purged, nil
+}
+func (r *SubscriptionRepo) GetForPeriod(
 	ctx context.Context,
 	filter models.GetTotalPriceFilter,
-) (int, error) {
+) ([]*domain.Subscription, error) {
 
-	query := `
-		SELECT COALESCE(SUM(price), 0)
-		FROM subscriptions
-	`
-	where := []string{}
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions"
+	where := []string{notDeletedCond}
 	args := []interface{}{}
 
 	if filter.UserID != nil {
@@ -152,6 +543,19 @@ func (r *SubscriptionRepo) GetTotalPrice(
 		args = append(args, *filter.ServiceName)
 	}
 
+	if filter.Tag != nil {
+		where = append(where, hasTagCond)
+		args = append(args, *filter.Tag)
+	}
+
+	if filter.Member != nil {
+		where = append(where, `(user_id = ? OR EXISTS (
+			SELECT 1 FROM subscription_members sm
+			WHERE sm.subscription_id = subscriptions.id AND sm.user_id = ?
+		))`)
+		args = append(args, *filter.Member, *filter.Member)
+	}
+
 	where = append(where, "start_date <= ?")
 	args = append(args, *filter.EndDate)
 
@@ -164,10 +568,19 @@ func (r *SubscriptionRepo) GetTotalPrice(
 	query += " WHERE " + strings.Join(where, " AND ")
 	query = sqlx.Rebind(sqlx.DOLLAR, query)
 
-	var total int
-	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
-		return 0, fmt.Errorf("subscriptionRepo.GetTotalPrice: %w", err)
+	subscriptions := make([]*models.Subscription, 0)
+	if err := r.db.SelectContext(ctx, &subscriptions, query, args...); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetForPeriod: %w", err)
+	}
+
+	if err := loadDetails(ctx, r.db, subscriptions); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetForPeriod: %w", err)
+	}
+
+	subscriptionsDomain := make([]*domain.Subscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		subscriptionsDomain = append(subscriptionsDomain, models.SubscriptionModelToDomain(s))
 	}
 
-	return total, nil
+	return subscriptionsDomain, nil
 }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// CreateBatch creates the subscriptions in a single transaction and returns
+// the error of every subscription that could not be created at its index.
+// In atomic mode the first such error rolls back the whole batch; otherwise
+// the failed subscriptions are skipped and the remaining ones are committed.
+func (r *SubscriptionRepo) CreateBatch(ctx context.Context, input []*domain.Subscription, atomic bool) ([]error, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.CreateBatch: %w", err)
+	}
+	defer tx.Rollback()
+
+	errs, err := applyBatch(ctx, tx, len(input), atomic, func(i int) error {
+		return createSubscription(ctx, tx, input[i])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.CreateBatch: %w", err)
+	}
+	if atomic && batchFailed(errs) {
+		return errs, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.CreateBatch: %w", err)
+	}
+	return errs, nil
+}
+
+// UpdateBatch applies the updates in a single transaction and returns the
+// errors the same way as CreateBatch: ErrNotFound for missing subscriptions,
+// ErrConflict for subscriptions whose version is not the one of their update,
+// or the database error of the update.
+func (r *SubscriptionRepo) UpdateBatch(ctx context.Context, input []models.SubscriptionBatchUpdate, atomic bool) ([]error, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.UpdateBatch: %w", err)
+	}
+	defer tx.Rollback()
+
+	errs, err := applyBatch(ctx, tx, len(input), atomic, func(i int) error {
+		return updateSubscription(ctx, tx, input[i].Id, input[i].Update)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.UpdateBatch: %w", err)
+	}
+	if atomic && batchFailed(errs) {
+		return errs, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.UpdateBatch: %w", err)
+	}
+	return errs, nil
+}
+
+// DeleteBatch deletes the subscriptions in a single transaction, reporting
+// the errors the same way as UpdateBatch. Versions are not checked.
+func (r *SubscriptionRepo) DeleteBatch(ctx context.Context, ids []string, atomic bool) ([]error, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.DeleteBatch: %w", err)
+	}
+	defer tx.Rollback()
+
+	errs, err := applyBatch(ctx, tx, len(ids), atomic, func(i int) error {
+		return deleteSubscription(ctx, tx, ids[i], nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.DeleteBatch: %w", err)
+	}
+	if atomic && batchFailed(errs) {
+		return errs, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.DeleteBatch: %w", err)
+	}
+	return errs, nil
+}
+
+// applyBatch applies the n items of a batch, each under a savepoint so that
+// a failed item is undone without aborting the transaction of the others.
+// It stops at the first failed item in atomic mode. Only a failure of the
+// savepoints themselves is returned as the error of the whole batch.
+func applyBatch(ctx context.Context, tx sqlx.ExecerContext, n int, atomic bool, apply func(i int) error) ([]error, error) {
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+			return nil, err
+		}
+		if err := apply(i); err != nil {
+			errs[i] = err
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT batch_item`); err != nil {
+				return nil, err
+			}
+			if atomic {
+				return errs, nil
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT batch_item`); err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+func batchFailed(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyBatch(t *testing.T) {
+	errValue := errors.New("value too long for type character varying(30)")
+	apply := func(i int) error {
+		if i == 1 {
+			return errValue
+		}
+		return nil
+	}
+	tests := []struct {
+		name    string
+		atomic  bool
+		errs    []error
+		queries []string
+	}{
+		{
+			name:   "a failed item is rolled back alone",
+			atomic: false,
+			errs:   []error{nil, errValue, nil},
+			queries: []string{
+				"SAVEPOINT batch_item", "RELEASE SAVEPOINT batch_item",
+				"SAVEPOINT batch_item", "ROLLBACK TO SAVEPOINT batch_item",
+				"SAVEPOINT batch_item", "RELEASE SAVEPOINT batch_item",
+			},
+		},
+		{
+			name:   "an atomic batch stops at the failed item",
+			atomic: true,
+			errs:   []error{nil, errValue, nil},
+			queries: []string{
+				"SAVEPOINT batch_item", "RELEASE SAVEPOINT batch_item",
+				"SAVEPOINT batch_item", "ROLLBACK TO SAVEPOINT batch_item",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &recordingExecer{}
+			errs, err := applyBatch(context.Background(), e, 3, tt.atomic, apply)
+			if err != nil {
+				t.Fatalf("applyBatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("errs = %v, want %v", errs, tt.errs)
+			}
+			if !reflect.DeepEqual(e.queries, tt.queries) {
+				t.Errorf("queries = %q, want %q", e.queries, tt.queries)
+			}
+		})
+	}
+}
+
+func TestApplyBatchSavepointFailure(t *testing.T) {
+	e := &recordingExecer{err: errors.New("connection reset")}
+	called := false
+	_, err := applyBatch(context.Background(), e, 1, false, func(int) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("applyBatch() error = %v, item applied = %v; want the batch to fail before the item", err, called)
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// streamChunkSize is the number of streamed subscriptions loaded at once.
+const streamChunkSize = 200
+
+// Stream calls fn for every subscription matching the filter in (created_at,
+// id) order. Subscriptions are read in keyset chunks like GetPage, so only a
+// chunk is held in memory at a time. All chunks are read within one
+// read-only transaction, which holds a single connection and sees a
+// consistent snapshot. Streaming stops at the first error returned by fn.
+func (r *SubscriptionRepo) Stream(ctx context.Context, filter models.SubscriptionFilter, fn func(*domain.Subscription) error) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.Stream: %w", err)
+	}
+	defer tx.Rollback()
+
+	var after *models.SubscriptionKey
+	for {
+		chunk, err := selectPage(ctx, tx, filter, after, streamChunkSize)
+		if err != nil {
+			return fmt.Errorf("subscriptionRepo.Stream: %w", err)
+		}
+		for _, s := range chunk {
+			if err := fn(models.SubscriptionModelToDomain(s)); err != nil {
+				return err
+			}
+		}
+		if len(chunk) < streamChunkSize {
+			break
+		}
+		last := chunk[len(chunk)-1]
+		after = &models.SubscriptionKey{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.Stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// AddMember adds a member to the subscription or replaces the share of an
+// existing one.
+func (r *SubscriptionRepo) AddMember(ctx context.Context, id string, input *domain.Member) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.AddMember: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := beginChange(ctx, tx, id, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO subscription_members (subscription_id, user_id, weight, fixed_amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, user_id) DO UPDATE
+		SET weight = EXCLUDED.weight, fixed_amount = EXCLUDED.fixed_amount
+	`, id, input.UserID, input.Weight, input.FixedAmount)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.AddMember: %w", err)
+	}
+	if err := recordChange(ctx, tx, before); err != nil {
+		return fmt.Errorf("subscriptionRepo.AddMember: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.AddMember: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) RemoveMember(ctx context.Context, id, userID string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.RemoveMember: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := beginChange(ctx, tx, id, nil)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM subscription_members WHERE subscription_id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.RemoveMember: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	if err := recordChange(ctx, tx, before); err != nil {
+		return fmt.Errorf("subscriptionRepo.RemoveMember: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.RemoveMember: %w", err)
+	}
+	return nil
+}
+
+// loadMembers fills the members of every subscription with a single query.
+func loadMembers(ctx context.Context, q sqlx.QueryerContext, subscriptions []*models.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(subscriptions))
+	byID := make(map[string]*models.Subscription, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.Id)
+		byID[s.Id] = s
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT subscription_id, user_id, weight, fixed_amount
+		FROM subscription_members
+		WHERE subscription_id IN (?)
+		ORDER BY created_at, user_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	members := make([]models.SubscriptionMember, 0)
+	if err := sqlx.SelectContext(ctx, q, &members, query, args...); err != nil {
+		return err
+	}
+	for _, m := range members {
+		s := byID[m.SubscriptionID]
+		s.Members = append(s.Members, m)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+func (r *SubscriptionRepo) GetPrices(ctx context.Context, id string) ([]domain.PriceChange, error) {
+	subscription := &models.Subscription{Id: id}
+	if err := lockSubscription(ctx, r.db, id); err != nil {
+		return nil, err
+	}
+	if err := loadPrices(ctx, r.db, []*models.Subscription{subscription}); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetPrices: %w", err)
+	}
+	return models.SubscriptionModelToDomain(subscription).Prices, nil
+}
+
+func (r *SubscriptionRepo) AddPrice(ctx context.Context, id string, input *domain.PriceChange) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.AddPrice: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := beginChange(ctx, tx, id, nil)
+	if err != nil {
+		return err
+	}
+	err = upsertPrice(ctx, tx, models.SubscriptionPrice{
+		SubscriptionID: id,
+		Price:          input.Price,
+		EffectiveFrom:  input.EffectiveFrom,
+	})
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.AddPrice: %w", err)
+	}
+	if err := recordChange(ctx, tx, before); err != nil {
+		return fmt.Errorf("subscriptionRepo.AddPrice: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.AddPrice: %w", err)
+	}
+	return nil
+}
+
+// loadPrices fills the price history of every subscription with a single query.
+func loadPrices(ctx context.Context, q sqlx.QueryerContext, subscriptions []*models.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(subscriptions))
+	byID := make(map[string]*models.Subscription, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.Id)
+		byID[s.Id] = s
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT subscription_id, price, effective_from
+		FROM subscription_prices
+		WHERE subscription_id IN (?)
+		ORDER BY effective_from
+	`, ids)
+	if err != nil {
+		return err
+	}
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	prices := make([]models.SubscriptionPrice, 0)
+	if err := sqlx.SelectContext(ctx, q, &prices, query, args...); err != nil {
+		return err
+	}
+	for _, p := range prices {
+		s := byID[p.SubscriptionID]
+		s.Prices = append(s.Prices, p)
+	}
+	return nil
+}
+
+// lockSubscription checks that the subscription exists and is not deleted,
+// locking its row when called within a transaction.
+func lockSubscription(ctx context.Context, q sqlx.QueryerContext, id string) error {
+	query := "SELECT id FROM subscriptions WHERE id = $1 AND " + notDeletedCond
+	if _, ok := q.(*sqlx.Tx); ok {
+		query += " FOR UPDATE"
+	}
+	var found string
+	if err := sqlx.GetContext(ctx, q, &found, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// bumpVersion increments the version of the subscription, locking its row
+// until the end of the transaction. When expected is set, it returns
+// ErrConflict unless the stored version equals it.
+func bumpVersion(ctx context.Context, tx *sqlx.Tx, id string, expected *int64) error {
+	query := "UPDATE subscriptions SET version = version + 1 WHERE id = $1 AND " + notDeletedCond
+	args := []interface{}{id}
+	if expected != nil {
+		query += " AND version = $2"
+		args = append(args, *expected)
+	}
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		if err := lockSubscription(ctx, tx, id); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return nil
+}
+
+func upsertPrice(ctx context.Context, tx *sqlx.Tx, price models.SubscriptionPrice) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO subscription_prices (subscription_id, price, effective_from)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price
+	`, price.SubscriptionID, price.Price, price.EffectiveFrom)
+	return err
+}
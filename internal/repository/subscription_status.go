@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// UpdateStatus stores the status, cancellation, end date and pauses of the
+// subscription. It returns ErrConflict when the stored status is no longer
+// from, which means the subscription was changed concurrently, and sets the
+// new version of the subscription on input. The change is recorded in the
+// outbox and the audit log.
+func (r *SubscriptionRepo) UpdateStatus(ctx context.Context, input *domain.Subscription, from domain.Status) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockSubscription(ctx, tx, input.Id); err != nil {
+		return err
+	}
+	before, err := getSubscription(ctx, tx, input.Id)
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+	}
+
+	subscription := models.SubscriptionDomainToModel(input)
+	var version int64
+	err = tx.GetContext(ctx, &version, `
+		UPDATE subscriptions SET status = $1, cancelled_at = $2, end_date = $3, version = version + 1
+		WHERE id = $4 AND status = $5 AND `+notDeletedCond+`
+		RETURNING version
+	`, subscription.Status, subscription.CancelledAt, subscription.EndDate, subscription.Id, string(from))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+	}
+	input.Version = version
+
+	for _, p := range subscription.Pauses {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO subscription_pauses (subscription_id, start_date, end_date)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (subscription_id, start_date) DO UPDATE SET end_date = EXCLUDED.end_date
+		`, p.SubscriptionID, p.StartDate, p.EndDate)
+		if err != nil {
+			return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+		}
+	}
+	if err := recordChange(ctx, tx, before); err != nil {
+		return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscriptionRepo.UpdateStatus: %w", err)
+	}
+	return nil
+}
+
+// loadDetails fills the price history, the pauses, the tags and the members
+// of every subscription.
+func loadDetails(ctx context.Context, q sqlx.QueryerContext, subscriptions []*models.Subscription) error {
+	if err := loadPrices(ctx, q, subscriptions); err != nil {
+		return err
+	}
+	if err := loadPauses(ctx, q, subscriptions); err != nil {
+		return err
+	}
+	if err := loadTags(ctx, q, subscriptions); err != nil {
+		return err
+	}
+	return loadMembers(ctx, q, subscriptions)
+}
+
+// loadPauses fills the pauses of every subscription with a single query.
+func loadPauses(ctx context.Context, q sqlx.QueryerContext, subscriptions []*models.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(subscriptions))
+	byID := make(map[string]*models.Subscription, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.Id)
+		byID[s.Id] = s
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT subscription_id, start_date, end_date
+		FROM subscription_pauses
+		WHERE subscription_id IN (?)
+		ORDER BY start_date
+	`, ids)
+	if err != nil {
+		return err
+	}
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	pauses := make([]models.SubscriptionPause, 0)
+	if err := sqlx.SelectContext(ctx, q, &pauses, query, args...); err != nil {
+		return err
+	}
+	for _, p := range pauses {
+		s := byID[p.SubscriptionID]
+		s.Pauses = append(s.Pauses, p)
+	}
+	return nil
+}
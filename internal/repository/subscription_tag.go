@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// hasTagCond matches subscriptions that have the tag bound to its placeholder.
+const hasTagCond = `EXISTS (
+	SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+	WHERE st.subscription_id = subscriptions.id AND t.name = ?
+)`
+
+// GetTagsByUserID returns the names of all tags used by the subscriptions of
+// a user.
+func (r *SubscriptionRepo) GetTagsByUserID(ctx context.Context, userID string) ([]string, error) {
+	tags := make([]string, 0)
+	err := r.db.SelectContext(ctx, &tags, `
+		SELECT DISTINCT t.name
+		FROM tags t
+		JOIN subscription_tags st ON st.tag_id = t.id
+		JOIN subscriptions s ON s.id = st.subscription_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NULL
+		ORDER BY t.name
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetTagsByUserID: %w", err)
+	}
+	return tags, nil
+}
+
+// replaceTags links the subscription to exactly the given tags, creating the
+// tags that do not exist yet.
+func replaceTags(ctx context.Context, tx *sqlx.Tx, subscriptionID string, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM subscription_tags WHERE subscription_id = $1", subscriptionID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		_, err := tx.ExecContext(ctx, `
+			WITH tag AS (
+				INSERT INTO tags (name) VALUES ($2)
+				ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+				RETURNING id
+			)
+			INSERT INTO subscription_tags (subscription_id, tag_id)
+			SELECT $1, id FROM tag
+		`, subscriptionID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTags fills the tags of every subscription with a single query.
+func loadTags(ctx context.Context, q sqlx.QueryerContext, subscriptions []*models.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(subscriptions))
+	byID := make(map[string]*models.Subscription, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.Id)
+		byID[s.Id] = s
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT st.subscription_id, t.name
+		FROM subscription_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id IN (?)
+		ORDER BY t.name
+	`, ids)
+	if err != nil {
+		return err
+	}
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	tags := make([]models.SubscriptionTag, 0)
+	if err := sqlx.SelectContext(ctx, q, &tags, query, args...); err != nil {
+		return err
+	}
+	for _, t := range tags {
+		s := byID[t.SubscriptionID]
+		s.Tags = append(s.Tags, t.Name)
+	}
+	return nil
+}
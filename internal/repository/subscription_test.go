@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+func TestSubscriptionFilterToWhere(t *testing.T) {
+	userID := "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+	prefix := "yan_"
+	minPrice, maxPrice := 100, 500
+	activeAt := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	noEndDate := true
+	tag := "work tools"
+	tests := []struct {
+		name   string
+		filter models.SubscriptionFilter
+		where  []string
+		args   []interface{}
+	}{
+		{
+			name:  "no filters",
+			where: []string{notDeletedCond},
+			args:  []interface{}{},
+		},
+		{
+			name:   "including deleted",
+			filter: models.SubscriptionFilter{IncludeDeleted: true},
+			where:  []string{},
+			args:   []interface{}{},
+		},
+		{
+			name:   "user and escaped service name prefix",
+			filter: models.SubscriptionFilter{UserID: &userID, ServiceNamePrefix: &prefix},
+			where:  []string{notDeletedCond, "user_id = ?", `service_name ILIKE ? ESCAPE '\'`},
+			args:   []interface{}{userID, `yan\_%`},
+		},
+		{
+			name:   "price range on the current price",
+			filter: models.SubscriptionFilter{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			where:  []string{notDeletedCond, currentPriceExpr + " >= ?", currentPriceExpr + " <= ?"},
+			args:   []interface{}{minPrice, maxPrice},
+		},
+		{
+			name:   "active at a date with no end date",
+			filter: models.SubscriptionFilter{ActiveAt: &activeAt, NoEndDate: &noEndDate},
+			where:  []string{notDeletedCond, "start_date <= ?", "(end_date >= ? OR end_date IS NULL)", "end_date IS NULL"},
+			args:   []interface{}{activeAt, activeAt},
+		},
+		{
+			name:   "tag",
+			filter: models.SubscriptionFilter{Tag: &tag},
+			where:  []string{notDeletedCond, hasTagCond},
+			args:   []interface{}{tag},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := subscriptionFilterToWhere(tt.filter)
+			if !reflect.DeepEqual(where, tt.where) {
+				t.Errorf("where = %q, want %q", where, tt.where)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+			if placeholders := strings.Count(strings.Join(where, " "), "?"); placeholders != len(args) {
+				t.Errorf("%d placeholders for %d args", placeholders, len(args))
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for in, want := range map[string]string{
+		"Yandex":   "Yandex",
+		"100%":     `100\%`,
+		"a_b":      `a\_b`,
+		`back\sl`:  `back\\sl`,
+		`%_\mixed`: `\%\_\\mixed`,
+	} {
+		if got := escapeLike(in); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// GetByUserID returns the subscriptions of a user ordered by start date. When
+// activeAt is set only subscriptions active at that date are returned.
+func (r *SubscriptionRepo) GetByUserID(
+	ctx context.Context,
+	userID string,
+	activeAt *time.Time,
+	limit, offset int,
+) ([]*domain.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE user_id = ? AND " + notDeletedCond
+	args := []interface{}{userID}
+
+	if activeAt != nil {
+		query += " AND start_date <= ? AND (end_date >= ? OR end_date IS NULL)"
+		args = append(args, *activeAt, *activeAt)
+	}
+	query += " ORDER BY start_date, id"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	if offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, offset)
+	}
+	query = sqlx.Rebind(sqlx.DOLLAR, query)
+
+	subscriptions := make([]*models.Subscription, 0)
+	if err := r.db.SelectContext(ctx, &subscriptions, query, args...); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetByUserID: %w", err)
+	}
+
+	if err := loadDetails(ctx, r.db, subscriptions); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.GetByUserID: %w", err)
+	}
+
+	subscriptionsDomain := make([]*domain.Subscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		subscriptionsDomain = append(subscriptionsDomain, models.SubscriptionModelToDomain(s))
+	}
+
+	return subscriptionsDomain, nil
+}
+
+// CountByUserID returns the number of all subscriptions of a user and the
+// number of those active at activeAt.
+func (r *SubscriptionRepo) CountByUserID(ctx context.Context, userID string, activeAt time.Time) (*models.UserSubscriptionsCount, error) {
+	var count models.UserSubscriptionsCount
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE start_date <= $2 AND (end_date >= $2 OR end_date IS NULL)) AS active
+		FROM subscriptions
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	if err := r.db.GetContext(ctx, &count, query, userID, activeAt); err != nil {
+		return nil, fmt.Errorf("subscriptionRepo.CountByUserID: %w", err)
+	}
+	return &count, nil
+}
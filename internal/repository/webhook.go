@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// webhookEndpointColumns selects event types as a space separated string.
+const webhookEndpointColumns = "id, url, secret, array_to_string(event_types, ' ') AS event_types, created_at"
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+// WebhookRepo stores webhook endpoints and the queue of their deliveries.
+type WebhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepo {
+	return &WebhookRepo{
+		db: db,
+	}
+}
+
+func (r *WebhookRepo) CreateEndpoint(ctx context.Context, input *domain.WebhookEndpoint) error {
+	eventTypes := make([]string, 0, len(input.EventTypes))
+	for _, t := range input.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_endpoints (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, string_to_array($4, ' '), $5)
+	`, input.Id, input.URL, input.Secret, strings.Join(eventTypes, " "), input.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("webhookRepo.CreateEndpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) GetEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	return r.selectEndpoints(ctx, "webhookRepo.GetEndpoints",
+		"SELECT "+webhookEndpointColumns+" FROM webhook_endpoints ORDER BY created_at, id")
+}
+
+// GetEndpointsByEventType returns the endpoints subscribed to the event type.
+func (r *WebhookRepo) GetEndpointsByEventType(ctx context.Context, eventType domain.EventType) ([]*domain.WebhookEndpoint, error) {
+	return r.selectEndpoints(ctx, "webhookRepo.GetEndpointsByEventType",
+		"SELECT "+webhookEndpointColumns+" FROM webhook_endpoints WHERE $1 = ANY(event_types) ORDER BY created_at, id",
+		string(eventType))
+}
+
+func (r *WebhookRepo) selectEndpoints(ctx context.Context, op, query string, args ...interface{}) ([]*domain.WebhookEndpoint, error) {
+	endpoints := make([]*models.WebhookEndpoint, 0)
+	if err := r.db.SelectContext(ctx, &endpoints, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	endpointsDomain := make([]*domain.WebhookEndpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		endpointsDomain = append(endpointsDomain, models.WebhookEndpointModelToDomain(e))
+	}
+	return endpointsDomain, nil
+}
+
+func (r *WebhookRepo) GetEndpointById(ctx context.Context, id string) (*domain.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	query := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints WHERE id = $1"
+	if err := r.db.GetContext(ctx, &endpoint, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("webhookRepo.GetEndpointById: %w", err)
+	}
+	return models.WebhookEndpointModelToDomain(&endpoint), nil
+}
+
+// DeleteEndpoint deletes the endpoint along with its deliveries.
+func (r *WebhookRepo) DeleteEndpoint(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("webhookRepo.DeleteEndpoint: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateDeliveries enqueues the deliveries in a single transaction. A
+// delivery of an event already enqueued for the endpoint is skipped, so
+// publishing the same event twice delivers it once.
+func (r *WebhookRepo) CreateDeliveries(ctx context.Context, input []*domain.WebhookDelivery) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("webhookRepo.CreateDeliveries: %w", err)
+	}
+	defer tx.Rollback()
+	for _, d := range input {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (endpoint_id, event_id) DO NOTHING
+		`, d.Id, d.EndpointID, d.EventID, string(d.EventType), string(d.Payload), string(d.Status), d.Attempts, d.NextAttemptAt, d.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("webhookRepo.CreateDeliveries: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("webhookRepo.CreateDeliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDeliveries picks up to limit pending deliveries due at now and leases
+// them by moving their next attempt to the end of the lease, so that
+// concurrent workers skip them while they are sent. A delivery whose worker
+// dies is picked up again once its lease expires.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	deliveries := make([]*models.WebhookDelivery, 0)
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	err := r.db.SelectContext(ctx, &deliveries, query, now.Add(lease), string(domain.DeliveryPending), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("webhookRepo.ClaimDeliveries: %w", err)
+	}
+	deliveriesDomain := make([]*domain.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		deliveriesDomain = append(deliveriesDomain, models.WebhookDeliveryModelToDomain(d))
+	}
+	return deliveriesDomain, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt.
+func (r *WebhookRepo) UpdateDelivery(ctx context.Context, input *domain.WebhookDelivery) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6
+		WHERE id = $7
+	`, string(input.Status), input.Attempts, input.NextAttemptAt, input.LastStatusCode, input.LastError, input.DeliveredAt, input.Id)
+	if err != nil {
+		return fmt.Errorf("webhookRepo.UpdateDelivery: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetDeliveries returns a page of the deliveries of an endpoint, newest
+// first, and their total count.
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	where := []string{"endpoint_id = ?"}
+	args := []interface{}{filter.EndpointID}
+	if filter.Status != nil {
+		where = append(where, "status = ?")
+		args = append(args, *filter.Status)
+	}
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE " + strings.Join(where, " AND ")
+	countQuery := "SELECT COUNT(*) FROM webhook_deliveries WHERE " + strings.Join(where, " AND ")
+	countArgs := append([]interface{}{}, args...)
+
+	query += " ORDER BY created_at DESC, id"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	if offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, offset)
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err := r.db.SelectContext(ctx, &deliveries, sqlx.Rebind(sqlx.DOLLAR, query), args...); err != nil {
+		return nil, 0, fmt.Errorf("webhookRepo.GetDeliveries: %w", err)
+	}
+	var count int
+	if err := r.db.GetContext(ctx, &count, sqlx.Rebind(sqlx.DOLLAR, countQuery), countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("webhookRepo.GetDeliveries: %w", err)
+	}
+
+	deliveriesDomain := make([]*domain.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		deliveriesDomain = append(deliveriesDomain, models.WebhookDeliveryModelToDomain(d))
+	}
+	return deliveriesDomain, count, nil
+}
+
+func (r *WebhookRepo) GetDeliveryById(ctx context.Context, endpointID, id string) (*domain.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE endpoint_id = $1 AND id = $2"
+	if err := r.db.GetContext(ctx, &delivery, query, endpointID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("webhookRepo.GetDeliveryById: %w", err)
+	}
+	return models.WebhookDeliveryModelToDomain(&delivery), nil
+}
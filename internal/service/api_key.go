@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// apiKeyPrefix starts every plaintext key, which looks like
+// "sa_<prefix>_<secret>". The prefix identifies the key and the secret
+// authenticates it.
+const apiKeyPrefix = "sa"
+
+type APIKeySvc struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) *APIKeySvc {
+	return &APIKeySvc{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+func (s *APIKeySvc) CreateAPIKey(ctx context.Context, input *dto.CreateAPIKeyInput) (*dto.CreateAPIKeyOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	for _, scope := range input.Scopes {
+		if !auth.IsValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+	plaintext, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := domain.NewAPIKey(uuid.NewString(), input.Name, prefix, hash, input.Scopes)
+	if err != nil {
+		return nil, ErrInvalidScope
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &dto.CreateAPIKeyOutput{
+		APIKeyOutput: apiKeyToOutput(key),
+		Key:          plaintext,
+	}, nil
+}
+
+func (s *APIKeySvc) GetAllAPIKeys(ctx context.Context) ([]*dto.APIKeyOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	keys, err := s.apiKeyRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]*dto.APIKeyOutput, 0, len(keys))
+	for _, k := range keys {
+		key := apiKeyToOutput(k)
+		output = append(output, &key)
+	}
+	return output, nil
+}
+
+func (s *APIKeySvc) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.apiKeyRepo.Revoke(ctx, id, time.Now().UTC()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RotateAPIKey issues new key material for an active key, keeping its ID,
+// name and scopes.
+func (s *APIKeySvc) RotateAPIKey(ctx context.Context, id string) (*dto.CreateAPIKeyOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	plaintext, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.apiKeyRepo.Rotate(ctx, id, prefix, hash, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return &dto.CreateAPIKeyOutput{
+		APIKeyOutput: apiKeyToOutput(key),
+		Key:          plaintext,
+	}, nil
+}
+
+// Authenticate resolves a plaintext API key to the identity of its caller.
+func (s *APIKeySvc) Authenticate(ctx context.Context, plaintext string) (*auth.Identity, error) {
+	parts := strings.SplitN(plaintext, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if key.IsRevoked() || subtle.ConstantTimeCompare([]byte(hashAPIKey(plaintext)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	return &auth.Identity{
+		APIKeyID: key.Id,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func apiKeyToOutput(key *domain.APIKey) dto.APIKeyOutput {
+	return dto.APIKeyOutput{
+		ID:        key.Id,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RotatedAt: key.RotatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// generateAPIKey returns a new plaintext key together with its lookup prefix
+// and the hash to store.
+func generateAPIKey() (plaintext, prefix, hash string, err error) {
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(prefixBytes)
+	plaintext = apiKeyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return plaintext, prefix, hashAPIKey(plaintext), nil
+}
+
+// hashAPIKey uses a plain SHA-256 since keys are random and long enough not
+// to need a slow password hash.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
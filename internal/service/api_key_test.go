@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// apiKeyRepo keeps API keys in memory.
+type apiKeyRepo struct {
+	repository.APIKeyRepository
+	keys map[string]*domain.APIKey
+}
+
+func newAPIKeyRepo() *apiKeyRepo {
+	return &apiKeyRepo{keys: make(map[string]*domain.APIKey)}
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, input *domain.APIKey) error {
+	r.keys[input.Id] = input
+	return nil
+}
+
+func (r *apiKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	key, ok := r.keys[id]
+	if !ok || key.IsRevoked() {
+		return repository.ErrNotFound
+	}
+	key.RevokedAt = &revokedAt
+	return nil
+}
+
+func (r *apiKeyRepo) Rotate(ctx context.Context, id, prefix, keyHash string, rotatedAt time.Time) (*domain.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok || key.IsRevoked() {
+		return nil, repository.ErrNotFound
+	}
+	key.Prefix, key.KeyHash, key.RotatedAt = prefix, keyHash, &rotatedAt
+	return key, nil
+}
+
+func createTestAPIKey(t *testing.T, s *APIKeySvc, scopes ...string) *dto.CreateAPIKeyOutput {
+	t.Helper()
+	output, err := s.CreateAPIKey(adminContext(), &dto.CreateAPIKeyInput{Name: "billing", Scopes: scopes})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	return output
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	repo := newAPIKeyRepo()
+	s := NewAPIKeyService(repo)
+	output := createTestAPIKey(t, s, auth.ScopeSubscriptionsRead, auth.ScopeReportsRead)
+
+	if !strings.HasPrefix(output.Key, apiKeyPrefix+"_"+output.Prefix+"_") {
+		t.Errorf("key %q does not start with its prefix %q", output.Key, output.Prefix)
+	}
+	stored := repo.keys[output.ID]
+	if stored.KeyHash == output.Key || strings.Contains(stored.KeyHash, output.Key) {
+		t.Error("plaintext key is stored")
+	}
+	identity, err := s.Authenticate(context.Background(), output.Key)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if identity.APIKeyID != output.ID || identity.Subject != "" || identity.Admin {
+		t.Errorf("identity = %+v, want a service caller with key %s", identity, output.ID)
+	}
+	if !reflect.DeepEqual(identity.Scopes, []string{auth.ScopeSubscriptionsRead, auth.ScopeReportsRead}) {
+		t.Errorf("scopes = %q", identity.Scopes)
+	}
+	if identity.HasScope(auth.ScopeSubscriptionsWrite) {
+		t.Error("identity has a scope the key was not given")
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	s := NewAPIKeyService(newAPIKeyRepo())
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		input dto.CreateAPIKeyInput
+		err   error
+	}{
+		{
+			name:  "not an admin",
+			ctx:   userContext("user"),
+			input: dto.CreateAPIKeyInput{Name: "billing", Scopes: []string{auth.ScopeReportsRead}},
+			err:   ErrForbidden,
+		},
+		{
+			name:  "unknown scope",
+			ctx:   adminContext(),
+			input: dto.CreateAPIKeyInput{Name: "billing", Scopes: []string{"subscriptions:delete"}},
+			err:   ErrInvalidScope,
+		},
+		{
+			name:  "no scopes",
+			ctx:   adminContext(),
+			input: dto.CreateAPIKeyInput{Name: "billing"},
+			err:   ErrInvalidScope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateAPIKey(tt.ctx, &tt.input); !errors.Is(err, tt.err) {
+				t.Errorf("CreateAPIKey() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsInvalidKeys(t *testing.T) {
+	s := NewAPIKeyService(newAPIKeyRepo())
+	output := createTestAPIKey(t, s, auth.ScopeReportsRead)
+	wrongSecret := []byte(output.Key)
+	wrongSecret[len(wrongSecret)-1] ^= 1
+
+	for name, key := range map[string]string{
+		"empty":          "",
+		"other scheme":   strings.Replace(output.Key, apiKeyPrefix+"_", "sk_", 1),
+		"unknown prefix": apiKeyPrefix + "_00000000_secret",
+		"wrong secret":   string(wrongSecret),
+	} {
+		if _, err := s.Authenticate(context.Background(), key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("%s: Authenticate() error = %v, want %v", name, err, ErrInvalidAPIKey)
+		}
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	s := NewAPIKeyService(newAPIKeyRepo())
+	output := createTestAPIKey(t, s, auth.ScopeReportsRead)
+
+	if err := s.RevokeAPIKey(adminContext(), output.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := s.Authenticate(context.Background(), output.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Authenticate() with a revoked key error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+	if err := s.RevokeAPIKey(adminContext(), "missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey() of a missing key error = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	s := NewAPIKeyService(newAPIKeyRepo())
+	old := createTestAPIKey(t, s, auth.ScopeReportsRead)
+
+	rotated, err := s.RotateAPIKey(adminContext(), old.ID)
+	if err != nil {
+		t.Fatalf("RotateAPIKey() error = %v", err)
+	}
+	if rotated.ID != old.ID || rotated.Key == old.Key || rotated.RotatedAt == nil {
+		t.Errorf("rotated key = %+v, want new key material for key %s", rotated, old.ID)
+	}
+	if _, err := s.Authenticate(context.Background(), old.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Authenticate() with the old key error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+	if _, err := s.Authenticate(context.Background(), rotated.Key); err != nil {
+		t.Errorf("Authenticate() with the rotated key error = %v", err)
+	}
+}
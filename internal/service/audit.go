@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+type AuditSvc struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) *AuditSvc {
+	return &AuditSvc{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditLog returns the changes of all subscriptions matching the filter,
+// newest first.
+func (s *AuditSvc) GetAuditLog(ctx context.Context, input dto.GetAuditLogInput) (*dto.GetAuditLogOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if input.Filter.Action != nil && !domain.AuditAction(*input.Filter.Action).IsValid() {
+		return nil, ErrInvalidAuditAction
+	}
+	entries, total, err := s.auditRepo.GetAll(ctx, models.AuditFilter{
+		SubscriptionID: input.Filter.SubscriptionID,
+		UserID:         input.Filter.UserID,
+		Actor:          input.Filter.Actor,
+		Action:         input.Filter.Action,
+		From:           input.Filter.From,
+		To:             input.Filter.To,
+	}, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return auditLogToOutput(entries, total), nil
+}
+
+func auditLogToOutput(entries []*domain.AuditEntry, total int) *dto.GetAuditLogOutput {
+	output := make([]*dto.AuditEntryOutput, 0, len(entries))
+	for _, e := range entries {
+		output = append(output, &dto.AuditEntryOutput{
+			ID:             e.Id,
+			SubscriptionID: e.SubscriptionID,
+			UserID:         e.UserID,
+			Action:         string(e.Action),
+			Actor:          e.Actor,
+			OnBehalfOf:     e.OnBehalfOf,
+			RequestID:      e.RequestID,
+			Before:         e.Before,
+			After:          e.After,
+			Changes:        e.Changes,
+			OccurredAt:     e.OccurredAt,
+		})
+	}
+	return &dto.GetAuditLogOutput{
+		Total:   total,
+		Entries: output,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// authorizeUser allows access to data of userID to admins, service callers
+// and the user themselves.
+func authorizeUser(ctx context.Context, userID string) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return ErrForbidden
+	}
+	if identity.AllUsers() || identity.Subject == userID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// scopeUserID restricts an optional user filter of non-admin callers to
+// their own subscriptions.
+func scopeUserID(ctx context.Context, userID *string) (*string, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrForbidden
+	}
+	if identity.AllUsers() {
+		return userID, nil
+	}
+	if userID != nil && *userID != identity.Subject {
+		return nil, ErrForbidden
+	}
+	return &identity.Subject, nil
+}
+
+// authorizeFilter restricts the subscription filter of non-admin callers to
+// their own subscriptions and allows only admins to include deleted ones.
+func authorizeFilter(ctx context.Context, filter *dto.SubscriptionFilter) error {
+	userID, err := scopeUserID(ctx, filter.UserID)
+	if err != nil {
+		return err
+	}
+	filter.UserID = userID
+	if filter.IncludeDeleted {
+		return authorizeAdmin(ctx)
+	}
+	return nil
+}
+
+// authorizeSubscription allows access to a subscription to admins, service
+// callers and its owner.
+func (s *SubscriptionSvc) authorizeSubscription(ctx context.Context, id string) error {
+	if identity, ok := auth.FromContext(ctx); ok && identity.AllUsers() {
+		return nil
+	}
+	subscription, err := s.subscriptionRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		return err
+	}
+	return authorizeUser(ctx, subscription.UserID)
+}
+
+// getAuthorizedSubscription loads a subscription the caller may access.
+func (s *SubscriptionSvc) getAuthorizedSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeUser(ctx, subscription.UserID); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// authorizeAdmin allows access to admin users only.
+func authorizeAdmin(ctx context.Context) error {
+	if identity, ok := auth.FromContext(ctx); ok && identity.Admin {
+		return nil
+	}
+	return ErrForbidden
+}
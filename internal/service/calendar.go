@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// CalendarSvc serves the renewals calendar feeds of users. Calendar apps
+// cannot send credentials, so every feed is protected by a token of its own
+// passed in the feed URL.
+type CalendarSvc struct {
+	calendarRepo     repository.CalendarRepository
+	subscriptionRepo repository.SubscriptionRepository
+}
+
+func NewCalendarService(calendarRepo repository.CalendarRepository, subscriptionRepo repository.SubscriptionRepository) *CalendarSvc {
+	return &CalendarSvc{
+		calendarRepo:     calendarRepo,
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+// CreateCalendarToken issues a new feed token for the user, invalidating the
+// previous one. The plaintext token is never returned again.
+func (s *CalendarSvc) CreateCalendarToken(ctx context.Context, userID string) (string, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	if err := s.calendarRepo.SetToken(ctx, userID, hashAPIKey(token), time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *CalendarSvc) RevokeCalendarToken(ctx context.Context, userID string) error {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.calendarRepo.DeleteToken(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCalendarTokenNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetRenewals returns the renewals of the subscriptions the user has active
+// now. It authenticates the caller by the feed token rather than the request
+// identity.
+func (s *CalendarSvc) GetRenewals(ctx context.Context, userID, token string) ([]*dto.RenewalOutput, error) {
+	hash, err := s.calendarRepo.GetTokenHash(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCalendarToken
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(token)), []byte(hash)) != 1 {
+		return nil, ErrInvalidCalendarToken
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetByUserID(ctx, userID, nil, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	renewals := make([]*dto.RenewalOutput, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.StatusAt(now) != domain.StatusActive {
+			continue
+		}
+		renewals = append(renewals, &dto.RenewalOutput{
+			ID:            subscription.Id,
+			ServiceName:   subscription.ServiceName,
+			Price:         subscription.PriceAt(now),
+			Currency:      subscription.Currency,
+			BillingPeriod: string(subscription.BillingPeriod),
+			StartDate:     subscription.StartDate,
+			Until:         subscription.LastBillingDay(),
+			SkippedDates:  subscription.SkippedBillingDates(),
+		})
+	}
+	return renewals, nil
+}
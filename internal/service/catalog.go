@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+type CatalogSvc struct {
+	serviceRepo repository.ServiceRepository
+}
+
+func NewCatalogService(serviceRepo repository.ServiceRepository) *CatalogSvc {
+	return &CatalogSvc{
+		serviceRepo: serviceRepo,
+	}
+}
+
+func (s *CatalogSvc) CreateService(ctx context.Context, input *dto.CreateServiceInput) (*dto.ServiceOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	service, err := domain.NewService(uuid.NewString(), input.Name, input.Aliases, input.Category, input.DefaultPrice, input.LogoURL)
+	if err != nil {
+		return nil, ErrInvalidService
+	}
+	if err := s.checkNamesAvailable(ctx, service); err != nil {
+		return nil, err
+	}
+	if err := s.serviceRepo.Create(ctx, service); err != nil {
+		return nil, err
+	}
+	return serviceToOutput(service), nil
+}
+
+func (s *CatalogSvc) GetAllServices(ctx context.Context) ([]*dto.ServiceOutput, error) {
+	services, err := s.serviceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]*dto.ServiceOutput, 0, len(services))
+	for _, service := range services {
+		output = append(output, serviceToOutput(service))
+	}
+	return output, nil
+}
+
+func (s *CatalogSvc) GetServiceById(ctx context.Context, id string) (*dto.ServiceOutput, error) {
+	service, err := s.serviceRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	return serviceToOutput(service), nil
+}
+
+func (s *CatalogSvc) UpdateService(ctx context.Context, id string, input *dto.UpdateServiceInput) (*dto.ServiceOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	current, err := s.serviceRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	name, aliases := current.Name, current.Aliases
+	category, defaultPrice, logoURL := current.Category, current.DefaultPrice, current.LogoURL
+	if input.Name != nil {
+		name = *input.Name
+	}
+	if input.Aliases != nil {
+		aliases = *input.Aliases
+	}
+	if input.Category != nil {
+		category = input.Category
+	}
+	if input.DefaultPrice != nil {
+		defaultPrice = input.DefaultPrice
+	}
+	if input.LogoURL != nil {
+		logoURL = input.LogoURL
+	}
+
+	service, err := domain.NewService(id, name, aliases, category, defaultPrice, logoURL)
+	if err != nil {
+		return nil, ErrInvalidService
+	}
+	service.CreatedAt = current.CreatedAt
+	if err := s.checkNamesAvailable(ctx, service); err != nil {
+		return nil, err
+	}
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	return serviceToOutput(service), nil
+}
+
+// DeleteService removes the service from the catalog. Subscriptions resolved
+// to it keep their service name.
+func (s *CatalogSvc) DeleteService(ctx context.Context, id string) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.serviceRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrServiceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkNamesAvailable makes sure that neither the name nor the aliases of
+// service resolve to another catalog entry.
+func (s *CatalogSvc) checkNamesAvailable(ctx context.Context, service *domain.Service) error {
+	for _, name := range service.Names() {
+		existing, err := s.serviceRepo.FindByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		if existing.Id != service.Id {
+			return ErrServiceConflict
+		}
+	}
+	return nil
+}
+
+func serviceToOutput(service *domain.Service) *dto.ServiceOutput {
+	return &dto.ServiceOutput{
+		ID:           service.Id,
+		Name:         service.Name,
+		Aliases:      service.Aliases,
+		Category:     service.Category,
+		DefaultPrice: service.DefaultPrice,
+		LogoURL:      service.LogoURL,
+		CreatedAt:    service.CreatedAt,
+	}
+}
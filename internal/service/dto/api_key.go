@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyInput struct {
+	Name   string
+	Scopes []string
+}
+type APIKeyOutput struct {
+	ID        string
+	Name      string
+	Prefix    string
+	Scopes    []string
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+// CreateAPIKeyOutput carries the plaintext key, which is never returned again.
+type CreateAPIKeyOutput struct {
+	APIKeyOutput
+	Key string
+}
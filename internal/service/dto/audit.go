@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// AuditFilter selects audit entries; From is inclusive and To exclusive.
+type AuditFilter struct {
+	SubscriptionID *string
+	UserID         *string
+	Actor          *string
+	Action         *string
+	From           *time.Time
+	To             *time.Time
+}
+type GetAuditLogInput struct {
+	Filter AuditFilter
+	Limit  int
+	Offset int
+}
+type GetSubscriptionHistoryInput struct {
+	Limit  int
+	Offset int
+}
+type AuditEntryOutput struct {
+	ID             string
+	SubscriptionID string
+	UserID         string
+	Action         string
+	Actor          string
+	OnBehalfOf     *string
+	RequestID      *string
+	// Before and After are JSON snapshots of the subscription, nil before
+	// its creation or restoration and after its deletion.
+	Before     []byte
+	After      []byte
+	Changes    []byte
+	OccurredAt time.Time
+}
+type GetAuditLogOutput struct {
+	Total   int
+	Entries []*AuditEntryOutput
+}
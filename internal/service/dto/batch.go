@@ -0,0 +1,18 @@
+package dto
+
+type BatchUpdateInput struct {
+	ID     string
+	Update UpdateSubscriptionInput
+}
+
+// BatchItemOutput is the result of a single item of a batch. Err is nil when
+// the item succeeded.
+type BatchItemOutput struct {
+	ID  string
+	Err error
+}
+type BatchOutput struct {
+	// Committed is false when an atomic batch was rolled back.
+	Committed bool
+	Items     []BatchItemOutput
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// RenewalOutput describes the recurring renewals of a subscription.
+type RenewalOutput struct {
+	ID            string
+	ServiceName   string
+	Price         int
+	Currency      string
+	BillingPeriod string
+	StartDate     time.Time
+	// Until is the last day the subscription can renew, nil when it has no
+	// end date.
+	Until *time.Time
+	// SkippedDates are the renewals within the trial or a finished pause.
+	SkippedDates []time.Time
+}
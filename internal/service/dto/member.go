@@ -0,0 +1,12 @@
+package dto
+
+type AddMemberInput struct {
+	UserID      string
+	Weight      *int
+	FixedAmount *int
+}
+type MemberOutput struct {
+	UserID      string
+	Weight      *int
+	FixedAmount *int
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type GetReportInput struct {
+	GroupBy     []string
+	UserID      *string
+	ServiceName *string
+	StartDate   time.Time
+	EndDate     time.Time
+	Currency    *string
+}
+type GetReportOutput struct {
+	Currency   string
+	GroupBy    []string
+	TotalPrice int
+	Rows       []*ReportRowOutput
+}
+
+// ReportRowOutput holds the spend of one group; only the keys listed in
+// GroupBy are set.
+type ReportRowOutput struct {
+	ServiceName *string
+	UserID      *string
+	Month       *time.Time
+	// Months counts the months billed to each subscription of the group.
+	Months     int
+	Charges    int
+	TotalPrice int
+}
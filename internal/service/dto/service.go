@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type CreateServiceInput struct {
+	Name         string
+	Aliases      []string
+	Category     *string
+	DefaultPrice *int
+	LogoURL      *string
+}
+type UpdateServiceInput struct {
+	Name         *string
+	Aliases      *[]string
+	Category     *string
+	DefaultPrice *int
+	LogoURL      *string
+}
+type ServiceOutput struct {
+	ID           string
+	Name         string
+	Aliases      []string
+	Category     *string
+	DefaultPrice *int
+	LogoURL      *string
+	CreatedAt    time.Time
+}
@@ -3,38 +3,173 @@ package dto
 import "time"
 
 type CreateSubscriptionInput struct {
-	ServiceName string
-	Price       int
-	UserID      string
-	StartDate   time.Time
-	EndDate     *time.Time
+	ServiceName   string
+	Price         int
+	Currency      string
+	BillingPeriod string
+	UserID        string
+	StartDate     time.Time
+	EndDate       *time.Time
+	TrialEndDate  *time.Time
+	Tags          []string
 }
 type GetAllSubscriptionsInput struct {
+	Filter SubscriptionFilter
+	Sort   []SortField
 	Limit  int
 	Offset int
+	// Cursor switches to keyset pagination when set; an empty cursor
+	// requests the first page.
+	Cursor *string
+}
+type SubscriptionFilter struct {
+	UserID            *string
+	ServiceName       *string
+	ServiceNamePrefix *string
+	MinPrice          *int
+	MaxPrice          *int
+	ActiveAt          *time.Time
+	StartDateFrom     *time.Time
+	StartDateTo       *time.Time
+	EndDateFrom       *time.Time
+	EndDateTo         *time.Time
+	NoEndDate         *bool
+	// TrialEndingWithin selects subscriptions whose trial ends within the
+	// given number of days from today.
+	TrialEndingWithin *int
+	Tag               *string
+	// IncludeDeleted selects deleted subscriptions as well; admins only.
+	IncludeDeleted bool
+}
+type SortField struct {
+	Field string
+	Desc  bool
 }
 type GetAllSubscriptionsOutput struct {
-	Total         int
+	// Total is not counted in cursor mode, where counting would scan every
+	// matching row on each page.
+	Total         *int
 	Subscriptions []*GetSubscriptionOutput
+	NextCursor    *string
 }
 type GetSubscriptionOutput struct {
-	ID          string
-	ServiceName string
-	Price       int
-	UserID      string
-	StartDate   time.Time
-	EndDate     *time.Time
+	ID            string
+	ServiceName   string
+	ServiceID     *string
+	Price         int
+	Currency      string
+	BillingPeriod string
+	MonthlyPrice  int
+	UserID        string
+	StartDate     time.Time
+	EndDate       *time.Time
+	TrialEndDate  *time.Time
+	Status        string
+	CancelledAt   *time.Time
+	Pauses        []PauseOutput
+	Tags          []string
+	Members       []MemberOutput
+	DeletedAt     *time.Time
+	Version       int64
+}
+type PauseOutput struct {
+	StartDate time.Time
+	EndDate   *time.Time
 }
 type UpdateSubscriptionInput struct {
-	ServiceName *string
-	Price       *int
-	UserID      *string
-	StartDate   *time.Time
-	EndDate     *time.Time
+	ServiceName   *string
+	Price         *int
+	Currency      *string
+	BillingPeriod *string
+	UserID        *string
+	StartDate     *time.Time
+	EndDate       *time.Time
+	TrialEndDate  *time.Time
+	// PriceEffectiveFrom is the date from which Price applies.
+	PriceEffectiveFrom *time.Time
+	// Tags replaces all tags of the subscription when set.
+	Tags *[]string
+	// Version makes the update fail with ErrVersionMismatch unless the
+	// subscription still has this version.
+	Version *int64
+}
+type SubscriptionPriceOutput struct {
+	Price         int
+	EffectiveFrom time.Time
+}
+type SchedulePriceInput struct {
+	Price         int
+	EffectiveFrom time.Time
 }
 type GetTotalPriceInput struct {
 	UserID      *string
 	ServiceName *string
+	Tag         *string
 	StartDate   *time.Time
 	EndDate     *time.Time
+	Currency    *string
+}
+type GetTotalPriceOutput struct {
+	TotalPrice    int
+	Currency      string
+	Breakdown     []*CurrencyTotalOutput
+	ByTag         []*TagTotalOutput
+	Subscriptions []*SubscriptionCostOutput
+	// Balance is set when the total is calculated for a single user.
+	Balance *BalanceOutput
+}
+
+// BalanceOutput compares what a user paid for the subscriptions they pay for
+// with their own shares of all subscriptions they take part in.
+type BalanceOutput struct {
+	// Paid is the full cost of the subscriptions the user pays for.
+	Paid int
+	// OwedToUser is the part of Paid that falls on other members.
+	OwedToUser int
+	// OwedByUser is the share of the user in subscriptions paid by others.
+	OwedByUser          int
+	PaidBreakdown       []*CurrencyTotalOutput
+	OwedToUserBreakdown []*CurrencyTotalOutput
+	OwedByUserBreakdown []*CurrencyTotalOutput
+}
+
+// TagTotalOutput is the spend on subscriptions with a tag. A subscription
+// with several tags counts towards each of them.
+type TagTotalOutput struct {
+	Tag        string
+	TotalPrice int
+	Breakdown  []*CurrencyTotalOutput
+}
+type CurrencyTotalOutput struct {
+	Currency       string
+	TotalPrice     int
+	ConvertedPrice int
+}
+type SubscriptionCostOutput struct {
+	ID            string
+	ServiceName   string
+	Price         int
+	Currency      string
+	BillingPeriod string
+	Tags          []string
+	// Months is the number of months of the window the subscription is
+	// billed for and Charges the number of billing dates within them.
+	Months  int
+	Charges int
+	Cost    int
+}
+type GetUserSubscriptionsInput struct {
+	ActiveOnly bool
+	Limit      int
+	Offset     int
+}
+type UserSubscriptionsCountOutput struct {
+	Total  int
+	Active int
+}
+type UserMonthlySpendOutput struct {
+	MonthlySpend  int
+	Currency      string
+	Subscriptions int
+	Breakdown     []*CurrencyTotalOutput
 }
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+type CreateWebhookInput struct {
+	URL string
+	// Secret signs the deliveries; a random one is generated when nil.
+	Secret     *string
+	EventTypes []string
+}
+type WebhookOutput struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// CreateWebhookOutput carries the signing secret, which is never returned
+// again.
+type CreateWebhookOutput struct {
+	WebhookOutput
+	Secret string
+}
+type GetWebhookDeliveriesInput struct {
+	Status *string
+	Limit  int
+	Offset int
+}
+type WebhookDeliveryOutput struct {
+	ID        string
+	EventID   string
+	EventType string
+	Payload   []byte
+	Status    string
+	Attempts  int
+	// NextAttemptAt is set for pending deliveries only.
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+type GetWebhookDeliveriesOutput struct {
+	Total      int
+	Deliveries []*WebhookDeliveryOutput
+}
@@ -3,5 +3,32 @@ package service
 import "errors"
 
 var (
-	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrInvalidSubscription   = errors.New("invalid subscription")
+	ErrUnsupportedCurrency   = errors.New("unsupported currency")
+	ErrInvalidPriceChange    = errors.New("invalid price change")
+	ErrInvalidSortField      = errors.New("invalid sort field")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrInvalidGroupBy        = errors.New("invalid group by")
+	ErrForbidden             = errors.New("forbidden")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrInvalidAPIKey         = errors.New("invalid api key")
+	ErrInvalidScope          = errors.New("invalid scope")
+	ErrInvalidTransition     = errors.New("invalid status transition")
+	ErrServiceNotFound       = errors.New("service not found")
+	ErrInvalidService        = errors.New("invalid service")
+	ErrServiceConflict       = errors.New("service name is already taken")
+	ErrInvalidMember         = errors.New("invalid member")
+	ErrMemberNotFound        = errors.New("member not found")
+	ErrBatchRolledBack       = errors.New("batch rolled back")
+	ErrBatchItemFailed       = errors.New("item could not be saved")
+	ErrInvalidCalendarToken  = errors.New("invalid calendar token")
+	ErrCalendarTokenNotFound = errors.New("calendar token not found")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrInvalidWebhook        = errors.New("invalid webhook")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrInvalidDeliveryStatus = errors.New("invalid delivery status")
+	ErrDeliveryNotDead       = errors.New("webhook delivery is not dead-lettered")
+	ErrInvalidAuditAction    = errors.New("invalid audit action")
+	ErrVersionMismatch       = errors.New("subscription version mismatch")
 )
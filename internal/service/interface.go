@@ -2,25 +2,112 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/notify"
 	"github.com/scmbr/subscription-aggregator/internal/repository"
 	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/internal/webhook"
+	"github.com/scmbr/subscription-aggregator/pkg/cursor"
 )
 
 type SubscriptionService interface {
 	CreateSubscription(ctx context.Context, input *dto.CreateSubscriptionInput) (string, error)
 	GetAllSubscriptions(ctx context.Context, input dto.GetAllSubscriptionsInput) (*dto.GetAllSubscriptionsOutput, error)
+	ExportSubscriptions(ctx context.Context, filter dto.SubscriptionFilter, fn func(*dto.GetSubscriptionOutput) error) error
 	GetSubscriptionById(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error)
 	UpdateSubscriptionById(ctx context.Context, id string, input *dto.UpdateSubscriptionInput) error
-	DeleteSubscriptionById(ctx context.Context, id string) error
-	GetSubscriptionsTotalPrice(ctx context.Context, input *dto.GetTotalPriceInput) (int, error)
+	DeleteSubscriptionById(ctx context.Context, id string, version *int64) error
+	RestoreSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error)
+	PurgeSubscriptions(ctx context.Context, before time.Time) (int64, error)
+	CreateSubscriptions(ctx context.Context, inputs []*dto.CreateSubscriptionInput, atomic bool) (*dto.BatchOutput, error)
+	UpdateSubscriptions(ctx context.Context, inputs []*dto.BatchUpdateInput, atomic bool) (*dto.BatchOutput, error)
+	DeleteSubscriptions(ctx context.Context, ids []string, atomic bool) (*dto.BatchOutput, error)
+	ImportSubscriptions(ctx context.Context, inputs []*dto.CreateSubscriptionInput, dryRun bool) (*dto.BatchOutput, error)
+	GetSubscriptionPrices(ctx context.Context, id string) ([]*dto.SubscriptionPriceOutput, error)
+	GetSubscriptionMembers(ctx context.Context, id string) ([]dto.MemberOutput, error)
+	AddSubscriptionMember(ctx context.Context, id string, input *dto.AddMemberInput) error
+	RemoveSubscriptionMember(ctx context.Context, id, userID string) error
+	PauseSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error)
+	ResumeSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error)
+	CancelSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error)
+	ScheduleSubscriptionPrice(ctx context.Context, id string, input *dto.SchedulePriceInput) error
+	GetUserSubscriptions(ctx context.Context, userID string, input dto.GetUserSubscriptionsInput) (*dto.GetAllSubscriptionsOutput, error)
+	CountUserSubscriptions(ctx context.Context, userID string) (*dto.UserSubscriptionsCountOutput, error)
+	GetUserMonthlySpend(ctx context.Context, userID string, currency *string) (*dto.UserMonthlySpendOutput, error)
+	GetUserTags(ctx context.Context, userID string) ([]string, error)
+	GetSubscriptionsTotalPrice(ctx context.Context, input *dto.GetTotalPriceInput) (*dto.GetTotalPriceOutput, error)
+	GetSpendReport(ctx context.Context, input *dto.GetReportInput) (*dto.GetReportOutput, error)
+	PublishRenewals(ctx context.Context, now time.Time) (int, error)
+	GetSubscriptionHistory(ctx context.Context, id string, input dto.GetSubscriptionHistoryInput) (*dto.GetAuditLogOutput, error)
+}
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, input *dto.CreateAPIKeyInput) (*dto.CreateAPIKeyOutput, error)
+	GetAllAPIKeys(ctx context.Context) ([]*dto.APIKeyOutput, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	RotateAPIKey(ctx context.Context, id string) (*dto.CreateAPIKeyOutput, error)
+	Authenticate(ctx context.Context, key string) (*auth.Identity, error)
+}
+type CatalogService interface {
+	CreateService(ctx context.Context, input *dto.CreateServiceInput) (*dto.ServiceOutput, error)
+	GetAllServices(ctx context.Context) ([]*dto.ServiceOutput, error)
+	GetServiceById(ctx context.Context, id string) (*dto.ServiceOutput, error)
+	UpdateService(ctx context.Context, id string, input *dto.UpdateServiceInput) (*dto.ServiceOutput, error)
+	DeleteService(ctx context.Context, id string) error
+}
+type CalendarService interface {
+	CreateCalendarToken(ctx context.Context, userID string) (string, error)
+	RevokeCalendarToken(ctx context.Context, userID string) error
+	GetRenewals(ctx context.Context, userID, token string) ([]*dto.RenewalOutput, error)
+}
+type ReminderService interface {
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+}
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, input *dto.CreateWebhookInput) (*dto.CreateWebhookOutput, error)
+	GetAllWebhooks(ctx context.Context) ([]*dto.WebhookOutput, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	GetWebhookDeliveries(ctx context.Context, id string, input dto.GetWebhookDeliveriesInput) (*dto.GetWebhookDeliveriesOutput, error)
+	RetryWebhookDelivery(ctx context.Context, id, deliveryID string) (*dto.WebhookDeliveryOutput, error)
+	DeliverWebhooks(ctx context.Context) (int, error)
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+type AuditService interface {
+	GetAuditLog(ctx context.Context, input dto.GetAuditLogInput) (*dto.GetAuditLogOutput, error)
 }
 type Service struct {
 	Subscription SubscriptionService
+	APIKey       APIKeyService
+	Catalog      CatalogService
+	Calendar     CalendarService
+	Reminder     ReminderService
+	Webhook      WebhookService
+	Audit        AuditService
+}
+type Deps struct {
+	Repos           *repository.Repository
+	ExchangeRates   exchange.RateProvider
+	DefaultCurrency string
+	Cursors         *cursor.Signer
+	Notifier        notify.Notifier
+	// ReminderDays is how many days ahead reminders are sent.
+	ReminderDays int
+	Webhooks     webhook.Sender
+	WebhookRetry domain.RetryPolicy
 }
 
-func NewService(repo *repository.Repository) *Service {
+func NewService(deps Deps) *Service {
+	webhooks := NewWebhookService(deps.Repos.Webhook, deps.Webhooks, deps.WebhookRetry)
 	return &Service{
-		Subscription: NewSubscriptionService(repo.Subscription),
+		Subscription: NewSubscriptionService(deps.Repos.Subscription, deps.Repos.Service, deps.Repos.Outbox, deps.Repos.Audit, deps.ExchangeRates, deps.DefaultCurrency, deps.Cursors),
+		APIKey:       NewAPIKeyService(deps.Repos.APIKey),
+		Catalog:      NewCatalogService(deps.Repos.Service),
+		Calendar:     NewCalendarService(deps.Repos.Calendar, deps.Repos.Subscription),
+		Reminder:     NewReminderService(deps.Repos.Subscription, deps.Repos.Notification, deps.Notifier, deps.ReminderDays),
+		Webhook:      webhooks,
+		Audit:        NewAuditService(deps.Repos.Audit),
 	}
 }
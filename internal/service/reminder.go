@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/notify"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+type ReminderSvc struct {
+	subscriptionRepo repository.SubscriptionRepository
+	notificationRepo repository.NotificationRepository
+	notifier         notify.Notifier
+	daysAhead        int
+}
+
+func NewReminderService(
+	subscriptionRepo repository.SubscriptionRepository,
+	notificationRepo repository.NotificationRepository,
+	notifier notify.Notifier,
+	daysAhead int,
+) *ReminderSvc {
+	return &ReminderSvc{
+		subscriptionRepo: subscriptionRepo,
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+		daysAhead:        daysAhead,
+	}
+}
+
+// SendReminders sends the reminders of the renewals and ends of
+// subscriptions due from today up to daysAhead days from now. Each reminder
+// is sent at most once: it is claimed before being sent and the claim is
+// released only when sending fails, so that it is retried on the next run.
+// It returns the number of reminders sent.
+func (s *ReminderSvc) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, s.daysAhead)
+	activeFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	reminders := make([]domain.Reminder, 0)
+	err := s.subscriptionRepo.Stream(ctx, models.SubscriptionFilter{
+		ActiveFrom: &activeFrom,
+		ActiveTo:   &to,
+	}, func(subscription *domain.Subscription) error {
+		reminders = append(reminders, subscription.RemindersBetween(from, to)...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range reminders {
+		reminder := &reminders[i]
+		claimed, err := s.notificationRepo.Claim(ctx, reminder, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.notifier.Notify(ctx, reminder); err != nil {
+			errs = append(errs, err)
+			if err := s.notificationRepo.Release(ctx, reminder); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// streamRepo serves Stream from a fixed list of subscriptions. Other
+// methods of the repository are not used by the reminders.
+type streamRepo struct {
+	repository.SubscriptionRepository
+	subscriptions []*domain.Subscription
+}
+
+func (r *streamRepo) Stream(ctx context.Context, filter models.SubscriptionFilter, fn func(*domain.Subscription) error) error {
+	for _, subscription := range r.subscriptions {
+		if err := fn(subscription); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimStore mimics sent_notifications: it outlives the services created
+// over it, as the table outlives restarts.
+type claimStore struct {
+	claimed map[string]bool
+}
+
+func reminderKey(reminder *domain.Reminder) string {
+	return fmt.Sprintf("%s/%s/%s", reminder.SubscriptionID, reminder.Kind, reminder.Date.Format(time.DateOnly))
+}
+
+func (s *claimStore) Claim(ctx context.Context, reminder *domain.Reminder, sentAt time.Time) (bool, error) {
+	key := reminderKey(reminder)
+	if s.claimed[key] {
+		return false, nil
+	}
+	s.claimed[key] = true
+	return true, nil
+}
+
+func (s *claimStore) Release(ctx context.Context, reminder *domain.Reminder) error {
+	delete(s.claimed, reminderKey(reminder))
+	return nil
+}
+
+// countingNotifier fails the first failures calls and counts the
+// reminders delivered after that.
+type countingNotifier struct {
+	failures  int
+	delivered int
+}
+
+func (n *countingNotifier) Notify(ctx context.Context, reminder *domain.Reminder) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("smtp: connection refused")
+	}
+	n.delivered++
+	return nil
+}
+
+func reminderFixture(t *testing.T) *streamRepo {
+	t.Helper()
+	subscription, err := domain.NewSubscription("3f8c3a4e-8d8a-4f55-9a4c-2b1f6a1c9d10", "Yandex Plus", 400, "RUB",
+		domain.BillingPeriodMonthly, "60601fee-2bf1-4721-ae6f-7636e79a0cba",
+		time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC), nil, nil)
+	if err != nil {
+		t.Fatalf("NewSubscription() error = %v", err)
+	}
+	return &streamRepo{subscriptions: []*domain.Subscription{subscription}}
+}
+
+func TestSendRemindersSendsOnceAcrossRestarts(t *testing.T) {
+	subscriptions := reminderFixture(t)
+	claims := &claimStore{claimed: map[string]bool{}}
+	notifier := &countingNotifier{}
+	now := time.Date(2025, time.July, 13, 9, 0, 0, 0, time.UTC)
+
+	sent, err := NewReminderService(subscriptions, claims, notifier, 3).SendReminders(context.Background(), now)
+	if err != nil || sent != 1 {
+		t.Fatalf("first run: SendReminders() = %d, %v, want 1, nil", sent, err)
+	}
+	// A new service over the same claims stands for a restarted process.
+	sent, err = NewReminderService(subscriptions, claims, notifier, 3).SendReminders(context.Background(), now.Add(time.Hour))
+	if err != nil || sent != 0 {
+		t.Fatalf("second run: SendReminders() = %d, %v, want 0, nil", sent, err)
+	}
+	if notifier.delivered != 1 {
+		t.Errorf("delivered = %d, want 1", notifier.delivered)
+	}
+}
+
+func TestSendRemindersRetriesFailedSend(t *testing.T) {
+	subscriptions := reminderFixture(t)
+	claims := &claimStore{claimed: map[string]bool{}}
+	notifier := &countingNotifier{failures: 1}
+	svc := NewReminderService(subscriptions, claims, notifier, 3)
+	now := time.Date(2025, time.July, 13, 9, 0, 0, 0, time.UTC)
+
+	sent, err := svc.SendReminders(context.Background(), now)
+	if err == nil || sent != 0 {
+		t.Fatalf("failing run: SendReminders() = %d, %v, want 0 and an error", sent, err)
+	}
+	if len(claims.claimed) != 0 {
+		t.Fatalf("claims after a failed send = %v, want none", claims.claimed)
+	}
+	sent, err = svc.SendReminders(context.Background(), now.Add(time.Hour))
+	if err != nil || sent != 1 {
+		t.Fatalf("retry: SendReminders() = %d, %v, want 1, nil", sent, err)
+	}
+	if notifier.delivered != 1 {
+		t.Errorf("delivered = %d, want 1", notifier.delivered)
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+const (
+	ReportGroupByServiceName = "service_name"
+	ReportGroupByUserID      = "user_id"
+	ReportGroupByMonth       = "month"
+)
+
+type reportKey struct {
+	serviceName string
+	userID      string
+	month       time.Time
+}
+
+type reportRow struct {
+	key     reportKey
+	months  int
+	charges int
+	total   float64
+}
+
+// GetSpendReport charges subscriptions the same way as
+// GetSubscriptionsTotalPrice and sums the converted costs per group. When
+// filtered or grouped by user, every user is charged their share of the
+// subscriptions they pay for or are members of.
+func (s *SubscriptionSvc) GetSpendReport(ctx context.Context, input *dto.GetReportInput) (*dto.GetReportOutput, error) {
+	groupBy, err := normalizeGroupBy(input.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	currency := s.defaultCurrency
+	if input.Currency != nil {
+		currency = *input.Currency
+	}
+	userID, err := scopeUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetForPeriod(ctx, models.GetTotalPriceFilter{
+		ServiceName: input.ServiceName,
+		Member:      userID,
+		StartDate:   &input.StartDate,
+		EndDate:     &input.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := userID != nil
+	for _, field := range groupBy {
+		byUser = byUser || field == ReportGroupByUserID
+	}
+	rates := make(map[string]float64)
+	rows := make(map[reportKey]*reportRow)
+	for _, subscription := range subscriptions {
+		rate, ok := rates[subscription.Currency]
+		if !ok {
+			rate, err = s.exchangeRates.Rate(ctx, subscription.Currency, currency)
+			if err != nil {
+				if errors.Is(err, exchange.ErrUnknownCurrency) {
+					return nil, ErrUnsupportedCurrency
+				}
+				return nil, err
+			}
+			rates[subscription.Currency] = rate
+		}
+		// shares splits a charge between the users it is reported for.
+		shares := func(charge int) map[string]int {
+			if !byUser {
+				return map[string]int{subscription.UserID: charge}
+			}
+			shares := subscription.Shares(charge)
+			if userID != nil {
+				return map[string]int{*userID: shares[*userID]}
+			}
+			return shares
+		}
+		rowFor := func(date time.Time, user string) *reportRow {
+			var key reportKey
+			for _, field := range groupBy {
+				switch field {
+				case ReportGroupByServiceName:
+					key.serviceName = subscription.ServiceName
+				case ReportGroupByUserID:
+					key.userID = user
+				case ReportGroupByMonth:
+					key.month = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+				}
+			}
+			r, ok := rows[key]
+			if !ok {
+				r = &reportRow{key: key}
+				rows[key] = r
+			}
+			return r
+		}
+		for _, month := range subscription.BilledMonths(input.StartDate, input.EndDate) {
+			for user := range shares(0) {
+				rowFor(month, user).months++
+			}
+		}
+		for _, date := range subscription.BillingDates(input.StartDate, input.EndDate) {
+			for user, share := range shares(subscription.PriceAt(date)) {
+				row := rowFor(date, user)
+				row.charges++
+				row.total += float64(share) * rate
+			}
+		}
+	}
+
+	return buildReport(rows, groupBy, currency), nil
+}
+
+func buildReport(rows map[reportKey]*reportRow, groupBy []string, currency string) *dto.GetReportOutput {
+	sorted := make([]*reportRow, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].key, sorted[j].key
+		for _, field := range groupBy {
+			switch field {
+			case ReportGroupByServiceName:
+				if a.serviceName != b.serviceName {
+					return a.serviceName < b.serviceName
+				}
+			case ReportGroupByUserID:
+				if a.userID != b.userID {
+					return a.userID < b.userID
+				}
+			case ReportGroupByMonth:
+				if !a.month.Equal(b.month) {
+					return a.month.Before(b.month)
+				}
+			}
+		}
+		return false
+	})
+
+	output := &dto.GetReportOutput{
+		Currency: currency,
+		GroupBy:  groupBy,
+		Rows:     make([]*dto.ReportRowOutput, 0, len(sorted)),
+	}
+	for _, row := range sorted {
+		out := &dto.ReportRowOutput{
+			Months:     row.months,
+			Charges:    row.charges,
+			TotalPrice: int(math.Round(row.total)),
+		}
+		for _, field := range groupBy {
+			switch field {
+			case ReportGroupByServiceName:
+				out.ServiceName = &row.key.serviceName
+			case ReportGroupByUserID:
+				out.UserID = &row.key.userID
+			case ReportGroupByMonth:
+				out.Month = &row.key.month
+			}
+		}
+		output.TotalPrice += out.TotalPrice
+		output.Rows = append(output.Rows, out)
+	}
+	return output
+}
+
+// normalizeGroupBy validates the grouping fields and drops duplicates.
+func normalizeGroupBy(groupBy []string) ([]string, error) {
+	seen := make(map[string]bool, len(groupBy))
+	normalized := make([]string, 0, len(groupBy))
+	for _, field := range groupBy {
+		switch field {
+		case ReportGroupByServiceName, ReportGroupByUserID, ReportGroupByMonth:
+		default:
+			return nil, ErrInvalidGroupBy
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		normalized = append(normalized, field)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// periodRepo serves GetForPeriod from a fixed list of subscriptions,
+// applying only the user filters.
+type periodRepo struct {
+	repository.SubscriptionRepository
+	subscriptions []*domain.Subscription
+}
+
+func (r *periodRepo) GetForPeriod(ctx context.Context, filter models.GetTotalPriceFilter) ([]*domain.Subscription, error) {
+	subscriptions := make([]*domain.Subscription, 0, len(r.subscriptions))
+	for _, subscription := range r.subscriptions {
+		if filter.UserID != nil && subscription.UserID != *filter.UserID {
+			continue
+		}
+		if filter.Member != nil && !sharedWith(subscription, *filter.Member) {
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+func sharedWith(subscription *domain.Subscription, userID string) bool {
+	if subscription.UserID == userID {
+		return true
+	}
+	for _, member := range subscription.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedSubscriptions returns a subscription of payer split evenly with
+// member and a subscription member pays alone.
+func sharedSubscriptions() []*domain.Subscription {
+	weight := 1
+	return []*domain.Subscription{
+		{
+			Id:            "shared",
+			ServiceName:   "Yandex Plus",
+			Price:         1000,
+			Currency:      "RUB",
+			BillingPeriod: domain.BillingPeriodMonthly,
+			UserID:        "payer",
+			StartDate:     month(2025, time.January),
+			Members:       []domain.Member{{UserID: "member", Weight: &weight}},
+		},
+		{
+			Id:            "own",
+			ServiceName:   "Kinopoisk",
+			Price:         300,
+			Currency:      "RUB",
+			BillingPeriod: domain.BillingPeriodMonthly,
+			UserID:        "member",
+			StartDate:     month(2025, time.January),
+		},
+	}
+}
+
+func newSharedSubscriptionSvc() *SubscriptionSvc {
+	return &SubscriptionSvc{
+		subscriptionRepo: &periodRepo{subscriptions: sharedSubscriptions()},
+		exchangeRates:    exchange.NewStaticRates("RUB", nil),
+		defaultCurrency:  "RUB",
+	}
+}
+
+func TestGetSpendReportChargesShares(t *testing.T) {
+	s := newSharedSubscriptionSvc()
+	member := "member"
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: member})
+
+	output, err := s.GetSpendReport(ctx, &dto.GetReportInput{
+		UserID:    &member,
+		StartDate: month(2025, time.January),
+		EndDate:   month(2025, time.March),
+	})
+	if err != nil {
+		t.Fatalf("GetSpendReport() error = %v", err)
+	}
+	if want := 3*500 + 3*300; output.TotalPrice != want {
+		t.Errorf("total price = %d, want %d", output.TotalPrice, want)
+	}
+}
+
+func TestGetSpendReportGroupedByUserSplitsShares(t *testing.T) {
+	s := newSharedSubscriptionSvc()
+
+	output, err := s.GetSpendReport(adminContext(), &dto.GetReportInput{
+		GroupBy:   []string{ReportGroupByUserID},
+		StartDate: month(2025, time.January),
+		EndDate:   month(2025, time.March),
+	})
+	if err != nil {
+		t.Fatalf("GetSpendReport() error = %v", err)
+	}
+	want := []dto.ReportRowOutput{
+		{Months: 6, Charges: 6, TotalPrice: 3*500 + 3*300},
+		{Months: 3, Charges: 3, TotalPrice: 3 * 500},
+	}
+	users := []string{"member", "payer"}
+	if len(output.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(output.Rows), len(want))
+	}
+	for i, row := range output.Rows {
+		if row.UserID == nil || *row.UserID != users[i] {
+			t.Errorf("rows[%d].UserID = %v, want %s", i, row.UserID, users[i])
+		}
+		if row.Months != want[i].Months || row.Charges != want[i].Charges || row.TotalPrice != want[i].TotalPrice {
+			t.Errorf("rows[%d] = %+v, want %+v", i, *row, want[i])
+		}
+	}
+	if output.TotalPrice != 3*1000+3*300 {
+		t.Errorf("total price = %d, want %d", output.TotalPrice, 3*1000+3*300)
+	}
+}
+
+func TestGetUserMonthlySpendChargesShares(t *testing.T) {
+	s := newSharedSubscriptionSvc()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "member"})
+
+	output, err := s.GetUserMonthlySpend(ctx, "member", nil)
+	if err != nil {
+		t.Fatalf("GetUserMonthlySpend() error = %v", err)
+	}
+	if output.MonthlySpend != 500+300 || output.Subscriptions != 2 {
+		t.Errorf("monthly spend = %d over %d subscriptions, want %d over 2", output.MonthlySpend, output.Subscriptions, 500+300)
+	}
+}
+
+func TestNormalizeGroupBy(t *testing.T) {
+	tests := []struct {
+		groupBy []string
+		want    []string
+		err     error
+	}{
+		{groupBy: nil, want: []string{}},
+		{groupBy: []string{"month", "service_name"}, want: []string{"month", "service_name"}},
+		{groupBy: []string{"user_id", "month", "user_id"}, want: []string{"user_id", "month"}},
+		{groupBy: []string{"currency"}, err: ErrInvalidGroupBy},
+	}
+	for _, tt := range tests {
+		got, err := normalizeGroupBy(tt.groupBy)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("normalizeGroupBy(%q) error = %v, want %v", tt.groupBy, err, tt.err)
+			continue
+		}
+		if tt.err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("normalizeGroupBy(%q) = %q, want %q", tt.groupBy, got, tt.want)
+		}
+	}
+}
+
+func reportSubscriptions() []*domain.Subscription {
+	return []*domain.Subscription{
+		{
+			Id: "netflix", ServiceName: "Netflix", Price: 300, Currency: "RUB",
+			BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: month(2025, time.January),
+		},
+		{
+			Id: "spotify", ServiceName: "Spotify", Price: 10, Currency: "USD",
+			BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: month(2025, time.February),
+			EndDate: datePtr(month(2025, time.February)),
+		},
+		{
+			Id: "netflix-yearly", ServiceName: "Netflix", Price: 1200, Currency: "RUB",
+			BillingPeriod: domain.BillingPeriodYearly, UserID: "user", StartDate: month(2024, time.March),
+		},
+	}
+}
+
+func TestGetSpendReportGroupsByServiceAndMonth(t *testing.T) {
+	s := &SubscriptionSvc{
+		subscriptionRepo: &periodRepo{subscriptions: reportSubscriptions()},
+		exchangeRates:    exchange.NewStaticRates("RUB", map[string]float64{"USD": 81.5}),
+		defaultCurrency:  "RUB",
+	}
+	output, err := s.GetSpendReport(adminContext(), &dto.GetReportInput{
+		GroupBy:   []string{ReportGroupByServiceName, ReportGroupByMonth},
+		StartDate: month(2025, time.January),
+		EndDate:   month(2025, time.March),
+	})
+	if err != nil {
+		t.Fatalf("GetSpendReport() error = %v", err)
+	}
+	type row struct {
+		service                string
+		month                  time.Time
+		months, charges, total int
+	}
+	want := []row{
+		{"Netflix", month(2025, time.January), 2, 1, 300},
+		{"Netflix", month(2025, time.February), 2, 1, 300},
+		{"Netflix", month(2025, time.March), 2, 2, 300 + 1200},
+		{"Spotify", month(2025, time.February), 1, 1, 815},
+	}
+	if len(output.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(output.Rows), len(want))
+	}
+	for i, r := range output.Rows {
+		if r.ServiceName == nil || r.Month == nil || r.UserID != nil {
+			t.Fatalf("rows[%d] keys = %v, %v, %v, want service name and month only", i, r.ServiceName, r.Month, r.UserID)
+		}
+		got := row{*r.ServiceName, *r.Month, r.Months, r.Charges, r.TotalPrice}
+		if got != want[i] {
+			t.Errorf("rows[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if output.Currency != "RUB" || output.TotalPrice != 300+300+1500+815 {
+		t.Errorf("total = %d %s, want %d RUB", output.TotalPrice, output.Currency, 300+300+1500+815)
+	}
+}
+
+func TestGetSpendReportWithoutGroups(t *testing.T) {
+	usd := "USD"
+	s := &SubscriptionSvc{
+		subscriptionRepo: &periodRepo{subscriptions: reportSubscriptions()},
+		exchangeRates:    exchange.NewStaticRates("RUB", map[string]float64{"USD": 80}),
+		defaultCurrency:  "RUB",
+	}
+	output, err := s.GetSpendReport(adminContext(), &dto.GetReportInput{
+		StartDate: month(2025, time.January),
+		EndDate:   month(2025, time.March),
+		Currency:  &usd,
+	})
+	if err != nil {
+		t.Fatalf("GetSpendReport() error = %v", err)
+	}
+	// 2100 RUB at 80 RUB per USD plus 10 USD.
+	if len(output.Rows) != 1 || output.Rows[0].TotalPrice != 36 || output.TotalPrice != 36 {
+		t.Errorf("rows = %+v, total = %d, want a single row of 36 USD", output.Rows, output.TotalPrice)
+	}
+	if output.Rows[0].Charges != 5 || output.Rows[0].Months != 7 {
+		t.Errorf("charges = %d, months = %d, want 5 and 7", output.Rows[0].Charges, output.Rows[0].Months)
+	}
+}
+
+func TestGetSpendReportUnsupportedCurrency(t *testing.T) {
+	jpy := "JPY"
+	s := &SubscriptionSvc{
+		subscriptionRepo: &periodRepo{subscriptions: reportSubscriptions()},
+		exchangeRates:    exchange.NewStaticRates("RUB", map[string]float64{"USD": 80}),
+		defaultCurrency:  "RUB",
+	}
+	_, err := s.GetSpendReport(adminContext(), &dto.GetReportInput{
+		StartDate: month(2025, time.January),
+		EndDate:   month(2025, time.March),
+		Currency:  &jpy,
+	})
+	if !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("GetSpendReport() error = %v, want %v", err, ErrUnsupportedCurrency)
+	}
+}
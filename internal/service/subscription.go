@@ -3,63 +3,254 @@ package service
 import (
 	"context"
 	"errors"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
 	"github.com/scmbr/subscription-aggregator/internal/repository"
 	"github.com/scmbr/subscription-aggregator/internal/repository/models"
 	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/pkg/cursor"
 )
 
 type SubscriptionSvc struct {
 	subscriptionRepo repository.SubscriptionRepository
+	serviceRepo      repository.ServiceRepository
+	outboxRepo       repository.OutboxRepository
+	auditRepo        repository.AuditRepository
+	exchangeRates    exchange.RateProvider
+	defaultCurrency  string
+	cursors          *cursor.Signer
 }
 
-func NewSubscriptionService(subscriptionRepo repository.SubscriptionRepository) *SubscriptionSvc {
+func NewSubscriptionService(
+	subscriptionRepo repository.SubscriptionRepository,
+	serviceRepo repository.ServiceRepository,
+	outboxRepo repository.OutboxRepository,
+	auditRepo repository.AuditRepository,
+	exchangeRates exchange.RateProvider,
+	defaultCurrency string,
+	cursors *cursor.Signer,
+) *SubscriptionSvc {
 	return &SubscriptionSvc{
 		subscriptionRepo: subscriptionRepo,
+		serviceRepo:      serviceRepo,
+		outboxRepo:       outboxRepo,
+		auditRepo:        auditRepo,
+		exchangeRates:    exchangeRates,
+		defaultCurrency:  defaultCurrency,
+		cursors:          cursors,
 	}
 }
+
+// CreateSubscription resolves the service name to a catalog entry by its
+// canonical name or aliases. Services missing from the catalog are stored
+// under the name as given.
 func (s *SubscriptionSvc) CreateSubscription(ctx context.Context, input *dto.CreateSubscriptionInput) (string, error) {
+	subscription, err := s.newSubscription(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return "", err
+	}
+	return subscription.Id, nil
+}
+
+// newSubscription authorizes and validates the input and builds the
+// subscription to store.
+func (s *SubscriptionSvc) newSubscription(ctx context.Context, input *dto.CreateSubscriptionInput) (*domain.Subscription, error) {
+	if err := authorizeUser(ctx, input.UserID); err != nil {
+		return nil, err
+	}
 	id := uuid.NewString()
+	billingPeriod := domain.BillingPeriod(input.BillingPeriod)
+	if billingPeriod == "" {
+		billingPeriod = domain.BillingPeriodMonthly
+	}
+	currency := input.Currency
+	if currency == "" {
+		currency = s.defaultCurrency
+	}
+	if err := s.checkCurrency(ctx, currency); err != nil {
+		return nil, err
+	}
+	serviceName, serviceID, err := s.resolveService(ctx, input.ServiceName)
+	if err != nil {
+		return nil, err
+	}
 	subscriptionDomain, err := domain.NewSubscription(
 		id,
-		input.ServiceName,
+		serviceName,
 		input.Price,
+		currency,
+		billingPeriod,
 		input.UserID,
 		input.StartDate,
 		input.EndDate,
+		input.TrialEndDate,
 	)
 	if err != nil {
-		return "", err
+		return nil, ErrInvalidSubscription
+	}
+	if err := subscriptionDomain.SetTags(input.Tags); err != nil {
+		return nil, ErrInvalidSubscription
 	}
-	err = s.subscriptionRepo.Create(ctx, subscriptionDomain)
+	subscriptionDomain.ServiceID = serviceID
+	return subscriptionDomain, nil
+}
+
+// resolveService normalizes the service name and matches it to the catalog,
+// returning the canonical name and ID of the catalog service, or the
+// normalized name and no ID when the service is not in the catalog.
+func (s *SubscriptionSvc) resolveService(ctx context.Context, name string) (string, *string, error) {
+	name = domain.NormalizeServiceName(name)
+	service, err := s.serviceRepo.FindByName(ctx, name)
 	if err != nil {
-		return "", err
+		if errors.Is(err, repository.ErrNotFound) {
+			return name, nil, nil
+		}
+		return "", nil, err
 	}
-	return id, nil
+	return service.Name, &service.Id, nil
 }
+
+// checkCurrency returns ErrUnsupportedCurrency unless the exchange rate
+// provider can convert currency, so that totals covering the subscription
+// can always be calculated.
+func (s *SubscriptionSvc) checkCurrency(ctx context.Context, currency string) error {
+	if _, err := s.exchangeRates.Rate(ctx, currency, s.defaultCurrency); err != nil {
+		if errors.Is(err, exchange.ErrUnknownCurrency) {
+			return ErrUnsupportedCurrency
+		}
+		return err
+	}
+	return nil
+}
+
+// sortableFields whitelists the fields subscriptions can be sorted by.
+var sortableFields = map[string]bool{
+	"service_name": true,
+	"price":        true,
+	"user_id":      true,
+	"start_date":   true,
+	"end_date":     true,
+}
+
 func (s *SubscriptionSvc) GetAllSubscriptions(ctx context.Context, input dto.GetAllSubscriptionsInput) (*dto.GetAllSubscriptionsOutput, error) {
-	subscriptions, total, err := s.subscriptionRepo.GetAll(ctx, input.Limit, input.Offset)
+	if err := authorizeFilter(ctx, &input.Filter); err != nil {
+		return nil, err
+	}
+	if input.Cursor != nil {
+		return s.getSubscriptionsPage(ctx, input)
+	}
+	sortFields := make([]models.SortField, 0, len(input.Sort))
+	for _, field := range input.Sort {
+		if !sortableFields[field.Field] {
+			return nil, ErrInvalidSortField
+		}
+		sortFields = append(sortFields, models.SortField{
+			Field: field.Field,
+			Desc:  field.Desc,
+		})
+	}
+	subscriptions, total, err := s.subscriptionRepo.GetAll(ctx, subscriptionFilterToModel(input.Filter), sortFields, input.Limit, input.Offset)
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	subscriptionsDTO := make([]*dto.GetSubscriptionOutput, 0, len(subscriptions))
 	for _, s := range subscriptions {
-		subscriptionsDTO = append(subscriptionsDTO, &dto.GetSubscriptionOutput{
-			ID:          s.Id,
-			ServiceName: s.ServiceName,
-			Price:       s.Price,
-			UserID:      s.UserID,
-			StartDate:   s.StartDate,
-			EndDate:     s.EndDate,
+		subscriptionsDTO = append(subscriptionsDTO, subscriptionToOutput(s, now))
+	}
+	return &dto.GetAllSubscriptionsOutput{
+		Total:         &total,
+		Subscriptions: subscriptionsDTO,
+	}, nil
+}
+
+// pageCursor is the payload of the opaque cursor returned to clients.
+type pageCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// getSubscriptionsPage pages through subscriptions by (created_at, id), which
+// stays stable while rows are inserted and doesn't slow down on deep pages.
+func (s *SubscriptionSvc) getSubscriptionsPage(ctx context.Context, input dto.GetAllSubscriptionsInput) (*dto.GetAllSubscriptionsOutput, error) {
+	if len(input.Sort) > 0 {
+		return nil, ErrInvalidSortField
+	}
+	var after *models.SubscriptionKey
+	if *input.Cursor != "" {
+		var key pageCursor
+		if err := s.cursors.Decode(*input.Cursor, &key); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = &models.SubscriptionKey{
+			CreatedAt: key.CreatedAt,
+			Id:        key.ID,
+		}
+	}
+	subscriptions, err := s.subscriptionRepo.GetPage(ctx, subscriptionFilterToModel(input.Filter), after, input.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCursor *string
+	if len(subscriptions) > input.Limit {
+		subscriptions = subscriptions[:input.Limit]
+		last := subscriptions[len(subscriptions)-1]
+		next, err := s.cursors.Encode(pageCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.Id,
 		})
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = &next
+	}
+
+	now := time.Now()
+	subscriptionsDTO := make([]*dto.GetSubscriptionOutput, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		subscriptionsDTO = append(subscriptionsDTO, subscriptionToOutput(s, now))
 	}
 	return &dto.GetAllSubscriptionsOutput{
-		Total:         total,
 		Subscriptions: subscriptionsDTO,
+		NextCursor:    nextCursor,
 	}, nil
 }
+
+func subscriptionFilterToModel(filter dto.SubscriptionFilter) models.SubscriptionFilter {
+	var trialEndFrom, trialEndTo *time.Time
+	if filter.TrialEndingWithin != nil {
+		now := time.Now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		until := today.AddDate(0, 0, *filter.TrialEndingWithin+1)
+		trialEndFrom, trialEndTo = &today, &until
+	}
+	return models.SubscriptionFilter{
+		UserID:            filter.UserID,
+		ServiceName:       filter.ServiceName,
+		ServiceNamePrefix: filter.ServiceNamePrefix,
+		MinPrice:          filter.MinPrice,
+		MaxPrice:          filter.MaxPrice,
+		ActiveAt:          filter.ActiveAt,
+		StartDateFrom:     filter.StartDateFrom,
+		StartDateTo:       filter.StartDateTo,
+		EndDateFrom:       filter.EndDateFrom,
+		EndDateTo:         filter.EndDateTo,
+		NoEndDate:         filter.NoEndDate,
+		TrialEndFrom:      trialEndFrom,
+		TrialEndTo:        trialEndTo,
+		Tag:               normalizeTag(filter.Tag),
+		IncludeDeleted:    filter.IncludeDeleted,
+	}
+}
 func (s *SubscriptionSvc) GetSubscriptionById(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error) {
 	subscription, err := s.subscriptionRepo.GetById(ctx, id)
 	if err != nil {
@@ -68,24 +259,185 @@ func (s *SubscriptionSvc) GetSubscriptionById(ctx context.Context, id string) (*
 		}
 		return nil, err
 	}
+	if err := authorizeUser(ctx, subscription.UserID); err != nil {
+		return nil, err
+	}
+
+	return subscriptionToOutput(subscription, time.Now()), nil
+}
 
+// subscriptionToOutput reports the price and the status of the subscription
+// at now.
+func subscriptionToOutput(subscription *domain.Subscription, now time.Time) *dto.GetSubscriptionOutput {
+	pauses := make([]dto.PauseOutput, 0, len(subscription.Pauses))
+	for _, p := range subscription.Pauses {
+		pauses = append(pauses, dto.PauseOutput{
+			StartDate: p.StartDate,
+			EndDate:   p.EndDate,
+		})
+	}
 	return &dto.GetSubscriptionOutput{
-		ID:          subscription.Id,
-		ServiceName: subscription.ServiceName,
-		Price:       subscription.Price,
-		UserID:      subscription.UserID,
-		StartDate:   subscription.StartDate,
-		EndDate:     subscription.EndDate,
-	}, nil
+		ID:            subscription.Id,
+		ServiceName:   subscription.ServiceName,
+		ServiceID:     subscription.ServiceID,
+		Price:         subscription.PriceAt(now),
+		Currency:      subscription.Currency,
+		BillingPeriod: string(subscription.BillingPeriod),
+		MonthlyPrice:  subscription.MonthlyPrice(now),
+		UserID:        subscription.UserID,
+		StartDate:     subscription.StartDate,
+		EndDate:       subscription.EndDate,
+		TrialEndDate:  subscription.TrialEndDate,
+		Status:        string(subscription.StatusAt(now)),
+		CancelledAt:   subscription.CancelledAt,
+		Pauses:        pauses,
+		Tags:          subscription.Tags,
+		Members:       membersToOutput(subscription.Members),
+		DeletedAt:     subscription.DeletedAt,
+		Version:       subscription.Version,
+	}
 }
+
+// UpdateSubscriptionById updates the subscription in place, except for the
+// price: a new price is appended to the price history starting from
+// input.PriceEffectiveFrom, or from the current month or the start date,
+// whichever is later, when it is not set. Past prices cannot be rewritten.
 func (s *SubscriptionSvc) UpdateSubscriptionById(ctx context.Context, id string, input *dto.UpdateSubscriptionInput) error {
-	if err := s.subscriptionRepo.Update(ctx, id, models.SubscriptionUpdate{
-		ServiceName: input.ServiceName,
-		Price:       input.Price,
-		UserID:      input.UserID,
-		StartDate:   input.StartDate,
-		EndDate:     input.EndDate,
-	}); err != nil {
+	update, err := s.newSubscriptionUpdate(ctx, id, input)
+	if err != nil {
+		return err
+	}
+	if err := s.subscriptionRepo.Update(ctx, id, *update); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrVersionMismatch
+		}
+		return err
+	}
+	return nil
+}
+
+// newSubscriptionUpdate authorizes and validates the update of the
+// subscription.
+func (s *SubscriptionSvc) newSubscriptionUpdate(ctx context.Context, id string, input *dto.UpdateSubscriptionInput) (*models.SubscriptionUpdate, error) {
+	subscription, err := s.getAuthorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.UserID != nil {
+		if err := authorizeUser(ctx, *input.UserID); err != nil {
+			return nil, err
+		}
+	}
+	startDate, endDate, trialEndDate := subscription.StartDate, subscription.EndDate, subscription.TrialEndDate
+	if input.StartDate != nil {
+		startDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		endDate = input.EndDate
+	}
+	if input.TrialEndDate != nil {
+		trialEndDate = input.TrialEndDate
+	}
+	if err := domain.ValidateDates(startDate, endDate, trialEndDate); err != nil {
+		return nil, ErrInvalidSubscription
+	}
+	if input.Currency != nil {
+		if err := s.checkCurrency(ctx, *input.Currency); err != nil {
+			return nil, err
+		}
+	}
+	var effectiveFrom *time.Time
+	if input.Price != nil {
+		// A price set before the subscription starts replaces the price of
+		// its first month, as that is where the price history begins.
+		from := currentMonth()
+		if from.Before(startDate) {
+			from = startDate
+		}
+		if input.PriceEffectiveFrom != nil {
+			if input.PriceEffectiveFrom.Before(currentMonth()) {
+				return nil, ErrInvalidPriceChange
+			}
+			from = *input.PriceEffectiveFrom
+		}
+		if _, err := domain.NewPriceChange(*input.Price, from); err != nil {
+			return nil, ErrInvalidPriceChange
+		}
+		effectiveFrom = &from
+	}
+	var serviceName, serviceID *string
+	if input.ServiceName != nil {
+		name, id, err := s.resolveService(ctx, *input.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, ErrInvalidSubscription
+		}
+		serviceName, serviceID = &name, id
+	}
+	var tags *[]string
+	if input.Tags != nil {
+		normalized, err := domain.NormalizeTags(*input.Tags)
+		if err != nil {
+			return nil, ErrInvalidSubscription
+		}
+		tags = &normalized
+	}
+	return &models.SubscriptionUpdate{
+		ServiceName:        serviceName,
+		ServiceID:          serviceID,
+		Price:              input.Price,
+		Currency:           input.Currency,
+		BillingPeriod:      input.BillingPeriod,
+		UserID:             input.UserID,
+		StartDate:          input.StartDate,
+		EndDate:            input.EndDate,
+		TrialEndDate:       input.TrialEndDate,
+		PriceEffectiveFrom: effectiveFrom,
+		Tags:               tags,
+		Version:            input.Version,
+	}, nil
+}
+func (s *SubscriptionSvc) GetSubscriptionPrices(ctx context.Context, id string) ([]*dto.SubscriptionPriceOutput, error) {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	prices, err := s.subscriptionRepo.GetPrices(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	output := make([]*dto.SubscriptionPriceOutput, 0, len(prices))
+	for _, p := range prices {
+		output = append(output, &dto.SubscriptionPriceOutput{
+			Price:         p.Price,
+			EffectiveFrom: p.EffectiveFrom,
+		})
+	}
+	return output, nil
+}
+
+// ScheduleSubscriptionPrice adds a price change that takes effect in the
+// current or a future month, replacing a change already scheduled for the
+// same date.
+func (s *SubscriptionSvc) ScheduleSubscriptionPrice(ctx context.Context, id string, input *dto.SchedulePriceInput) error {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return err
+	}
+	if input.EffectiveFrom.Before(currentMonth()) {
+		return ErrInvalidPriceChange
+	}
+	change, err := domain.NewPriceChange(input.Price, input.EffectiveFrom)
+	if err != nil {
+		return ErrInvalidPriceChange
+	}
+	if err := s.subscriptionRepo.AddPrice(ctx, id, change); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrSubscriptionNotFound
 		}
@@ -93,24 +445,245 @@ func (s *SubscriptionSvc) UpdateSubscriptionById(ctx context.Context, id string,
 	}
 	return nil
 }
-func (s *SubscriptionSvc) DeleteSubscriptionById(ctx context.Context, id string) error {
-	if err := s.subscriptionRepo.Delete(ctx, id); err != nil {
+
+// currentMonth returns the first day of the current month, matching the
+// precision of subscription dates.
+func currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// DeleteSubscriptionById deletes the subscription. When version is set, it
+// returns ErrVersionMismatch unless the subscription still has this version.
+func (s *SubscriptionSvc) DeleteSubscriptionById(ctx context.Context, id string, version *int64) error {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return err
+	}
+	if err := s.subscriptionRepo.Delete(ctx, id, version); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrSubscriptionNotFound
 		}
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrVersionMismatch
+		}
 		return err
 	}
 	return nil
 }
-func (s *SubscriptionSvc) GetSubscriptionsTotalPrice(ctx context.Context, input *dto.GetTotalPriceInput) (int, error) {
-	total, err := s.subscriptionRepo.GetTotalPrice(ctx, models.GetTotalPriceFilter{
+
+// RestoreSubscription undoes the deletion of a subscription that has not
+// been purged yet.
+func (s *SubscriptionSvc) RestoreSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error) {
+	deleted, err := s.subscriptionRepo.GetDeletedById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeUser(ctx, deleted.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptionRepo.Restore(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	subscription, err := s.subscriptionRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return subscriptionToOutput(subscription, time.Now()), nil
+}
+
+// PurgeSubscriptions removes the subscriptions deleted before the given time
+// for good. They can no longer be restored.
+func (s *SubscriptionSvc) PurgeSubscriptions(ctx context.Context, before time.Time) (int64, error) {
+	return s.subscriptionRepo.Purge(ctx, before)
+}
+
+// GetSubscriptionsTotalPrice totals the subscriptions of all users, or, for a
+// single user, the subscriptions they pay for or share with their share of
+// every charge.
+func (s *SubscriptionSvc) GetSubscriptionsTotalPrice(ctx context.Context, input *dto.GetTotalPriceInput) (*dto.GetTotalPriceOutput, error) {
+	currency := s.defaultCurrency
+	if input.Currency != nil {
+		currency = *input.Currency
+	}
+	userID, err := scopeUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := s.subscriptionRepo.GetForPeriod(ctx, models.GetTotalPriceFilter{
 		ServiceName: input.ServiceName,
-		UserID:      input.UserID,
+		Tag:         normalizeTag(input.Tag),
+		Member:      userID,
 		StartDate:   input.StartDate,
 		EndDate:     input.EndDate,
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	output := calculateTotalPrice(subscriptions, *input.StartDate, *input.EndDate, userID)
+	if err := s.convertTotalPrice(ctx, output, currency); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// calculateTotalPrice charges every subscription the price in effect on each
+// of its billing dates that fall within the [from, to] window and sums the
+// costs per currency, overall and per tag. When userID is set, only the share
+// of the user in every charge is counted and their balance is calculated.
+func calculateTotalPrice(subscriptions []*domain.Subscription, from, to time.Time, userID *string) *dto.GetTotalPriceOutput {
+	output := &dto.GetTotalPriceOutput{
+		Subscriptions: make([]*dto.SubscriptionCostOutput, 0, len(subscriptions)),
+		Breakdown:     make([]*dto.CurrencyTotalOutput, 0),
+		ByTag:         make([]*dto.TagTotalOutput, 0),
+	}
+	if userID != nil {
+		output.Balance = &dto.BalanceOutput{}
+	}
+	totals := make(map[string]*dto.CurrencyTotalOutput)
+	tagTotals := make(map[string]*dto.TagTotalOutput)
+	for _, subscription := range subscriptions {
+		dates := subscription.BillingDates(from, to)
+		if len(dates) == 0 {
+			continue
+		}
+		cost, paid := 0, 0
+		for _, date := range dates {
+			charge := subscription.PriceAt(date)
+			if userID == nil {
+				cost += charge
+				continue
+			}
+			cost += subscription.Shares(charge)[*userID]
+			if subscription.UserID == *userID {
+				paid += charge
+			}
+		}
+		if balance := output.Balance; balance != nil {
+			if subscription.UserID == *userID {
+				balance.PaidBreakdown = addCost(balance.PaidBreakdown, subscription.Currency, paid)
+				balance.OwedToUserBreakdown = addCost(balance.OwedToUserBreakdown, subscription.Currency, paid-cost)
+			} else {
+				balance.OwedByUserBreakdown = addCost(balance.OwedByUserBreakdown, subscription.Currency, cost)
+			}
+		}
+		total, ok := totals[subscription.Currency]
+		if !ok {
+			total = &dto.CurrencyTotalOutput{Currency: subscription.Currency}
+			totals[subscription.Currency] = total
+			output.Breakdown = append(output.Breakdown, total)
+		}
+		total.TotalPrice += cost
+		for _, tag := range subscription.Tags {
+			tagTotal, ok := tagTotals[tag]
+			if !ok {
+				tagTotal = &dto.TagTotalOutput{Tag: tag}
+				tagTotals[tag] = tagTotal
+				output.ByTag = append(output.ByTag, tagTotal)
+			}
+			tagTotal.Breakdown = addCost(tagTotal.Breakdown, subscription.Currency, cost)
+		}
+		output.Subscriptions = append(output.Subscriptions, &dto.SubscriptionCostOutput{
+			ID:            subscription.Id,
+			ServiceName:   subscription.ServiceName,
+			Price:         subscription.PriceAt(dates[len(dates)-1]),
+			Currency:      subscription.Currency,
+			BillingPeriod: string(subscription.BillingPeriod),
+			Tags:          subscription.Tags,
+			Months:        len(subscription.BilledMonths(from, to)),
+			Charges:       len(dates),
+			Cost:          cost,
+		})
+	}
+	sort.Slice(output.Breakdown, func(i, j int) bool {
+		return output.Breakdown[i].Currency < output.Breakdown[j].Currency
+	})
+	sort.Slice(output.ByTag, func(i, j int) bool {
+		return output.ByTag[i].Tag < output.ByTag[j].Tag
+	})
+	for _, tagTotal := range output.ByTag {
+		sort.Slice(tagTotal.Breakdown, func(i, j int) bool {
+			return tagTotal.Breakdown[i].Currency < tagTotal.Breakdown[j].Currency
+		})
+	}
+	return output
+}
+
+// addCost adds cost to the total of currency in totals.
+func addCost(totals []*dto.CurrencyTotalOutput, currency string, cost int) []*dto.CurrencyTotalOutput {
+	for _, total := range totals {
+		if total.Currency == currency {
+			total.TotalPrice += cost
+			return totals
+		}
+	}
+	return append(totals, &dto.CurrencyTotalOutput{Currency: currency, TotalPrice: cost})
+}
+
+// normalizeTag brings a tag filter to the form tags are stored in. Invalid
+// tags are kept as is and simply match nothing.
+func normalizeTag(tag *string) *string {
+	if tag == nil {
+		return nil
+	}
+	normalized, err := domain.NormalizeTags([]string{*tag})
+	if err != nil || len(normalized) == 0 {
+		return tag
+	}
+	return &normalized[0]
+}
+
+// convertTotalPrice converts every per-currency total to currency and sets
+// their sum as the overall total, and does the same for every tag and the
+// balance.
+func (s *SubscriptionSvc) convertTotalPrice(ctx context.Context, output *dto.GetTotalPriceOutput, currency string) error {
+	total, err := s.convertTotals(ctx, output.Breakdown, currency)
+	if err != nil {
+		return err
+	}
+	output.Currency = currency
+	output.TotalPrice = total
+	for _, tagTotal := range output.ByTag {
+		if tagTotal.TotalPrice, err = s.convertTotals(ctx, tagTotal.Breakdown, currency); err != nil {
+			return err
+		}
+	}
+	if balance := output.Balance; balance != nil {
+		if balance.Paid, err = s.convertTotals(ctx, balance.PaidBreakdown, currency); err != nil {
+			return err
+		}
+		if balance.OwedToUser, err = s.convertTotals(ctx, balance.OwedToUserBreakdown, currency); err != nil {
+			return err
+		}
+		if balance.OwedByUser, err = s.convertTotals(ctx, balance.OwedByUserBreakdown, currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertTotals fills ConvertedPrice of every per-currency total and returns
+// their sum in currency.
+func (s *SubscriptionSvc) convertTotals(ctx context.Context, totals []*dto.CurrencyTotalOutput, currency string) (int, error) {
+	sum := 0
+	for _, total := range totals {
+		rate, err := s.exchangeRates.Rate(ctx, total.Currency, currency)
+		if err != nil {
+			if errors.Is(err, exchange.ErrUnknownCurrency) {
+				return 0, ErrUnsupportedCurrency
+			}
+			return 0, err
+		}
+		total.ConvertedPrice = int(math.Round(float64(total.TotalPrice) * rate))
+		sum += total.ConvertedPrice
 	}
-	return total, nil
+	return sum, nil
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// GetSubscriptionHistory returns the audit log of a subscription, newest
+// first. Users only see the changes made while they owned the subscription,
+// which keeps the history of deleted subscriptions available to them.
+func (s *SubscriptionSvc) GetSubscriptionHistory(ctx context.Context, id string, input dto.GetSubscriptionHistoryInput) (*dto.GetAuditLogOutput, error) {
+	userID, err := scopeUserID(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries, total, err := s.auditRepo.GetAll(ctx, models.AuditFilter{
+		SubscriptionID: &id,
+		UserID:         userID,
+	}, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		// Tell subscriptions without recorded changes apart from missing
+		// ones and ones of other users.
+		if _, err := s.getAuthorizedSubscription(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return auditLogToOutput(entries, total), nil
+}
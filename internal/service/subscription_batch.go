@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// batchItemErrors fail a single item of a batch rather than the whole batch.
+var batchItemErrors = []error{
+	ErrForbidden,
+	ErrSubscriptionNotFound,
+	ErrInvalidSubscription,
+	ErrInvalidPriceChange,
+	ErrUnsupportedCurrency,
+	ErrVersionMismatch,
+}
+
+func isBatchItemError(err error) bool {
+	for _, target := range batchItemErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSubscriptions creates the subscriptions in a single transaction. In
+// atomic mode nothing is created unless every item succeeds; otherwise the
+// items that succeed are created and the failed ones are reported.
+func (s *SubscriptionSvc) CreateSubscriptions(ctx context.Context, inputs []*dto.CreateSubscriptionInput, atomic bool) (*dto.BatchOutput, error) {
+	items := make([]dto.BatchItemOutput, len(inputs))
+	subscriptions := make([]*domain.Subscription, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		subscription, err := s.newSubscription(ctx, input)
+		if err != nil {
+			if !isBatchItemError(err) {
+				return nil, err
+			}
+			items[i].Err = err
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+		indexes = append(indexes, i)
+	}
+	if atomic && batchFailed(items) {
+		return rollBackBatch(items), nil
+	}
+
+	errs, err := s.subscriptionRepo.CreateBatch(ctx, subscriptions, atomic)
+	if err != nil {
+		return nil, err
+	}
+	output := batchOutput(items, indexes, errs, atomic)
+	if output.Committed {
+		for i, subscription := range subscriptions {
+			if items[indexes[i]].Err == nil {
+				items[indexes[i]].ID = subscription.Id
+			}
+		}
+	}
+	return output, nil
+}
+
+// UpdateSubscriptions applies the updates in a single transaction, either
+// all or nothing in atomic mode or skipping the failed items otherwise.
+func (s *SubscriptionSvc) UpdateSubscriptions(ctx context.Context, inputs []*dto.BatchUpdateInput, atomic bool) (*dto.BatchOutput, error) {
+	items := make([]dto.BatchItemOutput, len(inputs))
+	updates := make([]models.SubscriptionBatchUpdate, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		items[i].ID = input.ID
+		update, err := s.newSubscriptionUpdate(ctx, input.ID, &input.Update)
+		if err != nil {
+			if !isBatchItemError(err) {
+				return nil, err
+			}
+			items[i].Err = err
+			continue
+		}
+		updates = append(updates, models.SubscriptionBatchUpdate{
+			Id:     input.ID,
+			Update: *update,
+		})
+		indexes = append(indexes, i)
+	}
+	if atomic && batchFailed(items) {
+		return rollBackBatch(items), nil
+	}
+
+	errs, err := s.subscriptionRepo.UpdateBatch(ctx, updates, atomic)
+	if err != nil {
+		return nil, err
+	}
+	return batchOutput(items, indexes, errs, atomic), nil
+}
+
+// DeleteSubscriptions deletes the subscriptions in a single transaction,
+// either all or nothing in atomic mode or skipping the failed items
+// otherwise.
+func (s *SubscriptionSvc) DeleteSubscriptions(ctx context.Context, ids []string, atomic bool) (*dto.BatchOutput, error) {
+	items := make([]dto.BatchItemOutput, len(ids))
+	authorized := make([]string, 0, len(ids))
+	indexes := make([]int, 0, len(ids))
+	for i, id := range ids {
+		items[i].ID = id
+		if err := s.authorizeSubscription(ctx, id); err != nil {
+			if !isBatchItemError(err) {
+				return nil, err
+			}
+			items[i].Err = err
+			continue
+		}
+		authorized = append(authorized, id)
+		indexes = append(indexes, i)
+	}
+	if atomic && batchFailed(items) {
+		return rollBackBatch(items), nil
+	}
+
+	errs, err := s.subscriptionRepo.DeleteBatch(ctx, authorized, atomic)
+	if err != nil {
+		return nil, err
+	}
+	return batchOutput(items, indexes, errs, atomic), nil
+}
+
+// batchOutput records the repository errors of the items at indexes. Errors
+// other than a missing subscription or a version mismatch are wrapped in
+// ErrBatchItemFailed. An atomic batch is rolled back on the first error.
+func batchOutput(items []dto.BatchItemOutput, indexes []int, errs []error, atomic bool) *dto.BatchOutput {
+	for i, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, repository.ErrNotFound):
+			items[indexes[i]].Err = ErrSubscriptionNotFound
+		case errors.Is(err, repository.ErrConflict):
+			items[indexes[i]].Err = ErrVersionMismatch
+		default:
+			items[indexes[i]].Err = fmt.Errorf("%w: %w", ErrBatchItemFailed, err)
+		}
+	}
+	if atomic && batchFailed(items) {
+		return rollBackBatch(items)
+	}
+	return &dto.BatchOutput{Committed: true, Items: items}
+}
+
+func batchFailed(items []dto.BatchItemOutput) bool {
+	for _, item := range items {
+		if item.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBackBatch marks the items that did not fail themselves as rolled back.
+func rollBackBatch(items []dto.BatchItemOutput) *dto.BatchOutput {
+	for i := range items {
+		if items[i].Err == nil {
+			items[i].Err = ErrBatchRolledBack
+		}
+	}
+	return &dto.BatchOutput{Committed: false, Items: items}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+)
+
+// batchRepo fails the items of DeleteBatch with the errors at their index.
+type batchRepo struct {
+	repository.SubscriptionRepository
+	errs []error
+}
+
+func (r *batchRepo) DeleteBatch(ctx context.Context, ids []string, atomic bool) ([]error, error) {
+	errs := make([]error, len(ids))
+	for i := range ids {
+		errs[i] = r.errs[i]
+		if errs[i] != nil && atomic {
+			break
+		}
+	}
+	return errs, nil
+}
+
+func TestDeleteSubscriptionsFailsOnlyTheFailedItem(t *testing.T) {
+	dbErr := errors.New("deadlock detected")
+	ids := []string{"a", "b", "c", "d"}
+	s := &SubscriptionSvc{subscriptionRepo: &batchRepo{errs: []error{nil, dbErr, repository.ErrNotFound, nil}}}
+
+	output, err := s.DeleteSubscriptions(adminContext(), ids, false)
+	if err != nil {
+		t.Fatalf("DeleteSubscriptions() error = %v", err)
+	}
+	if !output.Committed {
+		t.Error("batch is not committed")
+	}
+	want := []error{nil, ErrBatchItemFailed, ErrSubscriptionNotFound, nil}
+	for i, item := range output.Items {
+		if item.ID != ids[i] {
+			t.Errorf("items[%d].ID = %q, want %q", i, item.ID, ids[i])
+		}
+		if !errors.Is(item.Err, want[i]) {
+			t.Errorf("items[%d].Err = %v, want %v", i, item.Err, want[i])
+		}
+	}
+	if !errors.Is(output.Items[1].Err, dbErr) {
+		t.Errorf("items[1].Err = %v, want it to wrap %v", output.Items[1].Err, dbErr)
+	}
+}
+
+func TestDeleteSubscriptionsAtomicRollsBack(t *testing.T) {
+	s := &SubscriptionSvc{subscriptionRepo: &batchRepo{errs: []error{nil, errors.New("deadlock detected"), nil}}}
+
+	output, err := s.DeleteSubscriptions(adminContext(), []string{"a", "b", "c"}, true)
+	if err != nil {
+		t.Fatalf("DeleteSubscriptions() error = %v", err)
+	}
+	if output.Committed {
+		t.Error("batch is committed")
+	}
+	want := []error{ErrBatchRolledBack, ErrBatchItemFailed, ErrBatchRolledBack}
+	for i, item := range output.Items {
+		if !errors.Is(item.Err, want[i]) {
+			t.Errorf("items[%d].Err = %v, want %v", i, item.Err, want[i])
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// ExportSubscriptions calls fn for every subscription matching the filter,
+// streaming them in creation order.
+func (s *SubscriptionSvc) ExportSubscriptions(ctx context.Context, filter dto.SubscriptionFilter, fn func(*dto.GetSubscriptionOutput) error) error {
+	if err := authorizeFilter(ctx, &filter); err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.subscriptionRepo.Stream(ctx, subscriptionFilterToModel(filter), func(subscription *domain.Subscription) error {
+		return fn(subscriptionToOutput(subscription, now))
+	})
+}
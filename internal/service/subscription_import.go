@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// ImportSubscriptions validates every imported subscription and creates all
+// of them in a single transaction, or none when any of them is invalid. A dry
+// run only reports the invalid ones.
+func (s *SubscriptionSvc) ImportSubscriptions(ctx context.Context, inputs []*dto.CreateSubscriptionInput, dryRun bool) (*dto.BatchOutput, error) {
+	items := make([]dto.BatchItemOutput, len(inputs))
+	subscriptions := make([]*domain.Subscription, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		subscription, err := s.newSubscription(ctx, input)
+		if err != nil {
+			if !isBatchItemError(err) {
+				return nil, err
+			}
+			items[i].Err = err
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+		indexes = append(indexes, i)
+	}
+	if dryRun || batchFailed(items) {
+		return &dto.BatchOutput{Committed: false, Items: items}, nil
+	}
+
+	errs, err := s.subscriptionRepo.CreateBatch(ctx, subscriptions, true)
+	if err != nil {
+		return nil, err
+	}
+	output := batchOutput(items, indexes, errs, true)
+	if output.Committed {
+		for i, subscription := range subscriptions {
+			items[i].ID = subscription.Id
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// createBatchRepo records the subscriptions created by CreateBatch and fails
+// the items at their index with errs.
+type createBatchRepo struct {
+	repository.SubscriptionRepository
+	errs    []error
+	created []*domain.Subscription
+}
+
+func (r *createBatchRepo) CreateBatch(ctx context.Context, input []*domain.Subscription, atomic bool) ([]error, error) {
+	errs := make([]error, len(input))
+	for i := range input {
+		if i < len(r.errs) && r.errs[i] != nil {
+			errs[i] = r.errs[i]
+			if atomic {
+				return errs, nil
+			}
+		}
+	}
+	r.created = input
+	return errs, nil
+}
+
+func importInput(serviceName string, price int) *dto.CreateSubscriptionInput {
+	return &dto.CreateSubscriptionInput{
+		ServiceName: serviceName,
+		Price:       price,
+		UserID:      "user",
+		StartDate:   month(2025, time.January),
+	}
+}
+
+func newImportSvc(repo *createBatchRepo) *SubscriptionSvc {
+	return &SubscriptionSvc{
+		subscriptionRepo: repo,
+		serviceRepo:      &catalogRepo{},
+		exchangeRates:    exchange.NewStaticRates("RUB", nil),
+		defaultCurrency:  "RUB",
+	}
+}
+
+func TestImportSubscriptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputs    []*dto.CreateSubscriptionInput
+		dryRun    bool
+		repoErrs  []error
+		committed bool
+		errs      []error
+	}{
+		{
+			name:      "valid rows",
+			inputs:    []*dto.CreateSubscriptionInput{importInput("Netflix", 999), importInput("Okko", 399)},
+			committed: true,
+			errs:      []error{nil, nil},
+		},
+		{
+			name:   "dry run",
+			inputs: []*dto.CreateSubscriptionInput{importInput("Netflix", 999), importInput("Okko", 399)},
+			dryRun: true,
+			errs:   []error{nil, nil},
+		},
+		{
+			name:   "an invalid row fails the import",
+			inputs: []*dto.CreateSubscriptionInput{importInput("Netflix", 999), importInput("Okko", -1)},
+			errs:   []error{nil, ErrInvalidSubscription},
+		},
+		{
+			name:     "a row failing in the database rolls back the import",
+			inputs:   []*dto.CreateSubscriptionInput{importInput("Netflix", 999), importInput("Okko", 399)},
+			repoErrs: []error{nil, errors.New("value too long")},
+			errs:     []error{ErrBatchRolledBack, ErrBatchItemFailed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &createBatchRepo{errs: tt.repoErrs}
+			output, err := newImportSvc(repo).ImportSubscriptions(userContext("user"), tt.inputs, tt.dryRun)
+			if err != nil {
+				t.Fatalf("ImportSubscriptions() error = %v", err)
+			}
+			if output.Committed != tt.committed {
+				t.Errorf("committed = %v, want %v", output.Committed, tt.committed)
+			}
+			if tt.committed != (repo.created != nil) {
+				t.Errorf("created %d subscriptions, committed = %v", len(repo.created), tt.committed)
+			}
+			for i, item := range output.Items {
+				if !errors.Is(item.Err, tt.errs[i]) {
+					t.Errorf("items[%d].Err = %v, want %v", i, item.Err, tt.errs[i])
+				}
+				if tt.committed && item.ID != repo.created[i].Id {
+					t.Errorf("items[%d].ID = %q, want %q", i, item.ID, repo.created[i].Id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+func (s *SubscriptionSvc) GetSubscriptionMembers(ctx context.Context, id string) ([]dto.MemberOutput, error) {
+	subscription, err := s.subscriptionRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeUser(ctx, subscription.UserID); err != nil {
+		return nil, err
+	}
+	return membersToOutput(subscription.Members), nil
+}
+
+// AddSubscriptionMember shares the subscription with a user, replacing the
+// share of the user if they are already a member.
+func (s *SubscriptionSvc) AddSubscriptionMember(ctx context.Context, id string, input *dto.AddMemberInput) error {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return err
+	}
+	member, err := domain.NewMember(input.UserID, input.Weight, input.FixedAmount)
+	if err != nil {
+		return ErrInvalidMember
+	}
+	if err := s.subscriptionRepo.AddMember(ctx, id, member); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SubscriptionSvc) RemoveSubscriptionMember(ctx context.Context, id, userID string) error {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return err
+	}
+	if err := s.subscriptionRepo.RemoveMember(ctx, id, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func membersToOutput(members []domain.Member) []dto.MemberOutput {
+	output := make([]dto.MemberOutput, 0, len(members))
+	for _, m := range members {
+		output = append(output, dto.MemberOutput{
+			UserID:      m.UserID,
+			Weight:      m.Weight,
+			FixedAmount: m.FixedAmount,
+		})
+	}
+	return output
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+)
+
+// renewalNamespace derives the IDs of renewal events from the subscription
+// and the billing date, so that a renewal recorded twice is published once.
+var renewalNamespace = uuid.MustParse("6f1c2a64-3f0e-4d3b-9a57-2b8c1e0d4f21")
+
+// PublishRenewals records a renewal event in the outbox for every
+// subscription charged on the day of now. Running it again on the same day
+// records nothing new. It returns the number of renewals found.
+func (s *SubscriptionSvc) PublishRenewals(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	events := make([]*domain.Event, 0)
+	err := s.subscriptionRepo.Stream(ctx, models.SubscriptionFilter{
+		ActiveFrom: &month,
+		ActiveTo:   &month,
+	}, func(subscription *domain.Subscription) error {
+		for _, date := range subscription.BillingDates(today, today) {
+			if !date.Equal(today) {
+				continue
+			}
+			renewal := date
+			id := uuid.NewSHA1(renewalNamespace, []byte(subscription.Id+"/"+date.Format("2006-01-02")))
+			event := domain.NewSubscriptionEvent(id.String(), domain.EventSubscriptionRenewed, subscription, now)
+			event.RenewalDate = &renewal
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err := s.outboxRepo.Add(ctx, events); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
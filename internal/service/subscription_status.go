@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+func (s *SubscriptionSvc) PauseSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error) {
+	return s.changeStatus(ctx, id, (*domain.Subscription).Pause)
+}
+
+func (s *SubscriptionSvc) ResumeSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error) {
+	return s.changeStatus(ctx, id, (*domain.Subscription).Resume)
+}
+
+func (s *SubscriptionSvc) CancelSubscription(ctx context.Context, id string) (*dto.GetSubscriptionOutput, error) {
+	return s.changeStatus(ctx, id, (*domain.Subscription).Cancel)
+}
+
+// changeStatus applies the transition to the subscription at the current
+// time and stores the result.
+func (s *SubscriptionSvc) changeStatus(
+	ctx context.Context,
+	id string,
+	transition func(*domain.Subscription, time.Time) error,
+) (*dto.GetSubscriptionOutput, error) {
+	subscription, err := s.subscriptionRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeUser(ctx, subscription.UserID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	from := subscription.Status
+	if err := transition(subscription, now); err != nil {
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+	if err := s.subscriptionRepo.UpdateStatus(ctx, subscription, from); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+	return subscriptionToOutput(subscription, now), nil
+}
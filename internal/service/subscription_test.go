@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestCalculateTotalPrice(t *testing.T) {
+	from, to := month(2025, time.January), month(2025, time.June)
+	tests := []struct {
+		name         string
+		subscription *domain.Subscription
+		// months, charges and cost are zero when the subscription is not
+		// charged within the window at all.
+		months, charges, cost int
+	}{
+		{
+			name: "active for the whole window",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2024, time.March),
+			},
+			months:  6,
+			charges: 6,
+			cost:    1800,
+		},
+		{
+			name: "partial first month",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2025, time.April),
+			},
+			months:  3,
+			charges: 3,
+			cost:    900,
+		},
+		{
+			name: "partial last month",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2024, time.March),
+				EndDate:       datePtr(month(2025, time.February)),
+			},
+			months:  2,
+			charges: 2,
+			cost:    600,
+		},
+		{
+			name: "end date before the window",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2024, time.March),
+				EndDate:       datePtr(month(2024, time.December)),
+			},
+		},
+		{
+			name: "open-ended",
+			subscription: &domain.Subscription{
+				Price:         100,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2025, time.June),
+			},
+			months:  1,
+			charges: 1,
+			cost:    100,
+		},
+		{
+			name: "yearly",
+			subscription: &domain.Subscription{
+				Price:         1200,
+				BillingPeriod: domain.BillingPeriodYearly,
+				StartDate:     month(2024, time.February),
+			},
+			months:  6,
+			charges: 1,
+			cost:    1200,
+		},
+		{
+			name: "price change within the window",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2024, time.March),
+				Prices: []domain.PriceChange{
+					{Price: 300, EffectiveFrom: month(2024, time.March)},
+					{Price: 400, EffectiveFrom: month(2025, time.May)},
+				},
+			},
+			months:  6,
+			charges: 6,
+			cost:    4*300 + 2*400,
+		},
+		{
+			name: "trial months are free",
+			subscription: &domain.Subscription{
+				Price:         300,
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     month(2025, time.January),
+				TrialEndDate:  datePtr(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)),
+			},
+			months:  5,
+			charges: 5,
+			cost:    1500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.subscription.Id = "id"
+			tt.subscription.Currency = "RUB"
+			output := calculateTotalPrice([]*domain.Subscription{tt.subscription}, from, to, nil)
+			if tt.charges == 0 {
+				if len(output.Subscriptions) != 0 || len(output.Breakdown) != 0 {
+					t.Fatalf("subscription is charged: %+v", output)
+				}
+				return
+			}
+			if len(output.Subscriptions) != 1 {
+				t.Fatalf("got %d subscriptions, want 1", len(output.Subscriptions))
+			}
+			got := output.Subscriptions[0]
+			if got.Months != tt.months || got.Charges != tt.charges || got.Cost != tt.cost {
+				t.Errorf("months = %d, charges = %d, cost = %d, want %d, %d and %d", got.Months, got.Charges, got.Cost, tt.months, tt.charges, tt.cost)
+			}
+			if len(output.Breakdown) != 1 || output.Breakdown[0].TotalPrice != tt.cost {
+				t.Errorf("breakdown = %+v, want a total of %d", output.Breakdown, tt.cost)
+			}
+		})
+	}
+}
+
+func TestCalculateTotalPriceSumsPerCurrency(t *testing.T) {
+	subscriptions := []*domain.Subscription{
+		{Id: "a", Price: 300, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2024, time.January)},
+		{Id: "b", Price: 10, Currency: "USD", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2025, time.February)},
+		{Id: "c", Price: 200, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2025, time.March)},
+	}
+	output := calculateTotalPrice(subscriptions, month(2025, time.January), month(2025, time.March), nil)
+	want := []dto.CurrencyTotalOutput{
+		{Currency: "RUB", TotalPrice: 3*300 + 200},
+		{Currency: "USD", TotalPrice: 2 * 10},
+	}
+	if len(output.Breakdown) != len(want) {
+		t.Fatalf("breakdown = %+v, want %+v", output.Breakdown, want)
+	}
+	for i, total := range output.Breakdown {
+		if *total != want[i] {
+			t.Errorf("breakdown[%d] = %+v, want %+v", i, *total, want[i])
+		}
+	}
+}
+
+func TestCheckCurrency(t *testing.T) {
+	s := &SubscriptionSvc{
+		exchangeRates:   exchange.NewStaticRates("RUB", map[string]float64{"USD": 81.5}),
+		defaultCurrency: "RUB",
+	}
+	for currency, want := range map[string]error{
+		"RUB": nil,
+		"USD": nil,
+		"JPY": ErrUnsupportedCurrency,
+	} {
+		if err := s.checkCurrency(context.Background(), currency); !errors.Is(err, want) {
+			t.Errorf("checkCurrency(%q) = %v, want %v", currency, err, want)
+		}
+	}
+}
+
+// subscriptionRepo serves GetById from a single subscription. Other methods
+// of the repository are not used by the tests.
+type subscriptionRepo struct {
+	repository.SubscriptionRepository
+	subscription *domain.Subscription
+}
+
+func (r *subscriptionRepo) GetById(ctx context.Context, id string) (*domain.Subscription, error) {
+	if id != r.subscription.Id {
+		return nil, repository.ErrNotFound
+	}
+	return r.subscription, nil
+}
+
+func adminContext() context.Context {
+	return auth.WithIdentity(context.Background(), &auth.Identity{Subject: "admin", Admin: true})
+}
+
+func TestNewSubscriptionUpdatePriceEffectiveFrom(t *testing.T) {
+	now := currentMonth()
+	price := 500
+	tests := []struct {
+		name          string
+		startDate     time.Time
+		effectiveFrom *time.Time
+		want          time.Time
+		err           error
+	}{
+		{
+			name:      "started subscription defaults to the current month",
+			startDate: now.AddDate(-1, 0, 0),
+			want:      now,
+		},
+		{
+			name:      "future subscription defaults to the start date",
+			startDate: now.AddDate(0, 3, 0),
+			want:      now.AddDate(0, 3, 0),
+		},
+		{
+			name:          "future date",
+			startDate:     now.AddDate(-1, 0, 0),
+			effectiveFrom: datePtr(now.AddDate(0, 2, 0)),
+			want:          now.AddDate(0, 2, 0),
+		},
+		{
+			name:          "past date rewrites the price history",
+			startDate:     now.AddDate(-1, 0, 0),
+			effectiveFrom: datePtr(now.AddDate(0, -1, 0)),
+			err:           ErrInvalidPriceChange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SubscriptionSvc{subscriptionRepo: &subscriptionRepo{subscription: &domain.Subscription{
+				Id:            "id",
+				UserID:        "user",
+				Price:         300,
+				Currency:      "RUB",
+				BillingPeriod: domain.BillingPeriodMonthly,
+				StartDate:     tt.startDate,
+			}}}
+			update, err := s.newSubscriptionUpdate(adminContext(), "id", &dto.UpdateSubscriptionInput{
+				Price:              &price,
+				PriceEffectiveFrom: tt.effectiveFrom,
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if update.PriceEffectiveFrom == nil || !update.PriceEffectiveFrom.Equal(tt.want) {
+				t.Errorf("effective from = %v, want %v", update.PriceEffectiveFrom, tt.want)
+			}
+		})
+	}
+}
+
+// catalogRepo finds services by their canonical name or an alias, ignoring
+// case.
+type catalogRepo struct {
+	repository.ServiceRepository
+	services []*domain.Service
+}
+
+func (r *catalogRepo) FindByName(ctx context.Context, name string) (*domain.Service, error) {
+	for _, service := range r.services {
+		if strings.EqualFold(service.Name, name) {
+			return service, nil
+		}
+		for _, alias := range service.Aliases {
+			if strings.EqualFold(alias, name) {
+				return service, nil
+			}
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestNewSubscriptionUpdateResolvesService(t *testing.T) {
+	kinopoisk := &domain.Service{Id: "kinopoisk", Name: "Kinopoisk", Aliases: []string{"Кинопоиск"}}
+	yandexPlus := "yandex-plus"
+	tests := []struct {
+		name        string
+		serviceName string
+		wantName    string
+		wantID      *string
+		err         error
+	}{
+		{name: "alias", serviceName: "  кинопоиск ", wantName: "Kinopoisk", wantID: &kinopoisk.Id},
+		{name: "not in the catalog", serviceName: "Local  Gym", wantName: "Local Gym"},
+		{name: "blank", serviceName: "   ", err: ErrInvalidSubscription},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SubscriptionSvc{
+				subscriptionRepo: &subscriptionRepo{subscription: &domain.Subscription{
+					Id:          "id",
+					ServiceName: "Yandex Plus",
+					ServiceID:   &yandexPlus,
+					UserID:      "user",
+					StartDate:   month(2025, time.January),
+				}},
+				serviceRepo: &catalogRepo{services: []*domain.Service{kinopoisk}},
+			}
+			update, err := s.newSubscriptionUpdate(adminContext(), "id", &dto.UpdateSubscriptionInput{ServiceName: &tt.serviceName})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if update.ServiceName == nil || *update.ServiceName != tt.wantName {
+				t.Errorf("service name = %v, want %q", update.ServiceName, tt.wantName)
+			}
+			if (update.ServiceID == nil) != (tt.wantID == nil) || tt.wantID != nil && *update.ServiceID != *tt.wantID {
+				t.Errorf("service ID = %v, want %v", update.ServiceID, tt.wantID)
+			}
+		})
+	}
+}
+
+// listRepo records the filter and sort GetAll is called with.
+type listRepo struct {
+	repository.SubscriptionRepository
+	filter models.SubscriptionFilter
+	sort   []models.SortField
+}
+
+func (r *listRepo) GetAll(ctx context.Context, filter models.SubscriptionFilter, sort []models.SortField, limit, offset int) ([]*domain.Subscription, int, error) {
+	r.filter, r.sort = filter, sort
+	return []*domain.Subscription{}, 0, nil
+}
+
+func TestGetAllSubscriptionsSort(t *testing.T) {
+	tests := []struct {
+		name string
+		sort []dto.SortField
+		err  error
+	}{
+		{name: "whitelisted fields", sort: []dto.SortField{{Field: "price"}, {Field: "start_date", Desc: true}}},
+		{name: "unknown field", sort: []dto.SortField{{Field: "created_at"}}, err: ErrInvalidSortField},
+		{name: "column injection", sort: []dto.SortField{{Field: "price; DROP TABLE subscriptions"}}, err: ErrInvalidSortField},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &listRepo{}
+			s := &SubscriptionSvc{subscriptionRepo: repo}
+			_, err := s.GetAllSubscriptions(adminContext(), dto.GetAllSubscriptionsInput{Sort: tt.sort})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if len(repo.sort) != len(tt.sort) {
+				t.Fatalf("sort = %+v, want %+v", repo.sort, tt.sort)
+			}
+			for i, field := range repo.sort {
+				if field.Field != tt.sort[i].Field || field.Desc != tt.sort[i].Desc {
+					t.Errorf("sort[%d] = %+v, want %+v", i, field, tt.sort[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllSubscriptionsScopesUsers(t *testing.T) {
+	repo := &listRepo{}
+	s := &SubscriptionSvc{subscriptionRepo: repo}
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "user"})
+
+	if _, err := s.GetAllSubscriptions(ctx, dto.GetAllSubscriptionsInput{}); err != nil {
+		t.Fatalf("GetAllSubscriptions() error = %v", err)
+	}
+	if repo.filter.UserID == nil || *repo.filter.UserID != "user" {
+		t.Errorf("user filter = %v, want the caller", repo.filter.UserID)
+	}
+	other := "other"
+	_, err := s.GetAllSubscriptions(ctx, dto.GetAllSubscriptionsInput{Filter: dto.SubscriptionFilter{UserID: &other}})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("listing another user: err = %v, want %v", err, ErrForbidden)
+	}
+}
+
+func TestCalculateTotalPriceByTag(t *testing.T) {
+	subscriptions := []*domain.Subscription{
+		{Id: "a", Price: 300, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2025, time.January), Tags: []string{"entertainment", "family"}},
+		{Id: "b", Price: 10, Currency: "USD", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2025, time.January), Tags: []string{"entertainment"}},
+		{Id: "c", Price: 200, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, StartDate: month(2025, time.January)},
+	}
+	output := calculateTotalPrice(subscriptions, month(2025, time.January), month(2025, time.February), nil)
+	want := map[string][]dto.CurrencyTotalOutput{
+		"entertainment": {{Currency: "RUB", TotalPrice: 600}, {Currency: "USD", TotalPrice: 20}},
+		"family":        {{Currency: "RUB", TotalPrice: 600}},
+	}
+	tags := []string{"entertainment", "family"}
+	if len(output.ByTag) != len(tags) {
+		t.Fatalf("by tag = %+v, want %q", output.ByTag, tags)
+	}
+	for i, tagTotal := range output.ByTag {
+		if tagTotal.Tag != tags[i] {
+			t.Errorf("by tag[%d] = %q, want %q", i, tagTotal.Tag, tags[i])
+			continue
+		}
+		if len(tagTotal.Breakdown) != len(want[tagTotal.Tag]) {
+			t.Errorf("%s breakdown = %+v, want %+v", tagTotal.Tag, tagTotal.Breakdown, want[tagTotal.Tag])
+			continue
+		}
+		for j, total := range tagTotal.Breakdown {
+			if *total != want[tagTotal.Tag][j] {
+				t.Errorf("%s breakdown[%d] = %+v, want %+v", tagTotal.Tag, j, *total, want[tagTotal.Tag][j])
+			}
+		}
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tag := "  Work   Tools "
+	if got := normalizeTag(&tag); got == nil || *got != "work tools" {
+		t.Errorf("normalizeTag(%q) = %v, want %q", tag, got, "work tools")
+	}
+	if got := normalizeTag(nil); got != nil {
+		t.Errorf("normalizeTag(nil) = %q, want nil", *got)
+	}
+}
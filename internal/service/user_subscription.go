@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+func (s *SubscriptionSvc) GetUserSubscriptions(ctx context.Context, userID string, input dto.GetUserSubscriptionsInput) (*dto.GetAllSubscriptionsOutput, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	var activeAt *time.Time
+	if input.ActiveOnly {
+		month := currentMonth()
+		activeAt = &month
+	}
+	subscriptions, err := s.subscriptionRepo.GetByUserID(ctx, userID, activeAt, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.subscriptionRepo.CountByUserID(ctx, userID, currentMonth())
+	if err != nil {
+		return nil, err
+	}
+
+	total := count.Total
+	if input.ActiveOnly {
+		total = count.Active
+	}
+	now := time.Now()
+	subscriptionsDTO := make([]*dto.GetSubscriptionOutput, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		subscriptionsDTO = append(subscriptionsDTO, subscriptionToOutput(s, now))
+	}
+	return &dto.GetAllSubscriptionsOutput{
+		Total:         &total,
+		Subscriptions: subscriptionsDTO,
+	}, nil
+}
+
+func (s *SubscriptionSvc) CountUserSubscriptions(ctx context.Context, userID string) (*dto.UserSubscriptionsCountOutput, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	count, err := s.subscriptionRepo.CountByUserID(ctx, userID, currentMonth())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserSubscriptionsCountOutput{
+		Total:  count.Total,
+		Active: count.Active,
+	}, nil
+}
+
+// GetUserTags returns the tags used by the subscriptions of a user.
+func (s *SubscriptionSvc) GetUserTags(ctx context.Context, userID string) ([]string, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.GetTagsByUserID(ctx, userID)
+}
+
+// GetUserMonthlySpend sums the monthly equivalents of the shares of the user
+// in the current prices of all subscriptions the user pays for or is a member
+// of that are active now.
+func (s *SubscriptionSvc) GetUserMonthlySpend(ctx context.Context, userID string, currency *string) (*dto.UserMonthlySpendOutput, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	target := s.defaultCurrency
+	if currency != nil {
+		target = *currency
+	}
+	month := currentMonth()
+	subscriptions, err := s.subscriptionRepo.GetForPeriod(ctx, models.GetTotalPriceFilter{
+		Member:    &userID,
+		StartDate: &month,
+		EndDate:   &month,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	breakdown := make([]*dto.CurrencyTotalOutput, 0)
+	totals := make(map[string]*dto.CurrencyTotalOutput)
+	charged := 0
+	for _, subscription := range subscriptions {
+		if subscription.StatusAt(now) != domain.StatusActive || subscription.InTrial(now) {
+			continue
+		}
+		charged++
+		total, ok := totals[subscription.Currency]
+		if !ok {
+			total = &dto.CurrencyTotalOutput{Currency: subscription.Currency}
+			totals[subscription.Currency] = total
+			breakdown = append(breakdown, total)
+		}
+		share := subscription.Shares(subscription.PriceAt(now))[userID]
+		total.TotalPrice += subscription.BillingPeriod.MonthlyPrice(share)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].Currency < breakdown[j].Currency
+	})
+
+	monthlySpend, err := s.convertTotals(ctx, breakdown, target)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserMonthlySpendOutput{
+		MonthlySpend:  monthlySpend,
+		Currency:      target,
+		Subscriptions: charged,
+		Breakdown:     breakdown,
+	}, nil
+}
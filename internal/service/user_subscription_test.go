@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/auth"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/exchange"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+)
+
+// userRepo records the arguments of GetByUserID and counts the
+// subscriptions of a user as count.
+type userRepo struct {
+	repository.SubscriptionRepository
+	count    models.UserSubscriptionsCount
+	calls    int
+	activeAt *time.Time
+	limit    int
+	offset   int
+}
+
+func (r *userRepo) GetByUserID(ctx context.Context, userID string, activeAt *time.Time, limit, offset int) ([]*domain.Subscription, error) {
+	r.calls++
+	r.activeAt, r.limit, r.offset = activeAt, limit, offset
+	return []*domain.Subscription{}, nil
+}
+
+func (r *userRepo) CountByUserID(ctx context.Context, userID string, activeAt time.Time) (*models.UserSubscriptionsCount, error) {
+	r.calls++
+	return &r.count, nil
+}
+
+func userContext(userID string) context.Context {
+	return auth.WithIdentity(context.Background(), &auth.Identity{Subject: userID})
+}
+
+func TestGetUserSubscriptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		activeOnly bool
+		total      int
+	}{
+		{name: "all", total: 5},
+		{name: "active now", activeOnly: true, total: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &userRepo{count: models.UserSubscriptionsCount{Total: 5, Active: 2}}
+			s := &SubscriptionSvc{subscriptionRepo: repo}
+			output, err := s.GetUserSubscriptions(userContext("user"), "user", dto.GetUserSubscriptionsInput{
+				ActiveOnly: tt.activeOnly,
+				Limit:      10,
+				Offset:     20,
+			})
+			if err != nil {
+				t.Fatalf("GetUserSubscriptions() error = %v", err)
+			}
+			if *output.Total != tt.total {
+				t.Errorf("total = %d, want %d", *output.Total, tt.total)
+			}
+			if tt.activeOnly != (repo.activeAt != nil) || repo.activeAt != nil && !repo.activeAt.Equal(currentMonth()) {
+				t.Errorf("active at = %v, want the current month only for active subscriptions", repo.activeAt)
+			}
+			if repo.limit != 10 || repo.offset != 20 {
+				t.Errorf("limit and offset = %d, %d, want 10, 20", repo.limit, repo.offset)
+			}
+		})
+	}
+}
+
+func TestUserEndpointsRejectOtherUsers(t *testing.T) {
+	repo := &userRepo{}
+	s := &SubscriptionSvc{subscriptionRepo: repo}
+	ctx := userContext("user")
+
+	if _, err := s.GetUserSubscriptions(ctx, "other", dto.GetUserSubscriptionsInput{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetUserSubscriptions() error = %v, want %v", err, ErrForbidden)
+	}
+	if _, err := s.CountUserSubscriptions(ctx, "other"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("CountUserSubscriptions() error = %v, want %v", err, ErrForbidden)
+	}
+	if _, err := s.GetUserMonthlySpend(ctx, "other", nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetUserMonthlySpend() error = %v, want %v", err, ErrForbidden)
+	}
+	if repo.calls != 0 {
+		t.Errorf("repository called %d times for another user", repo.calls)
+	}
+}
+
+func TestGetUserMonthlySpend(t *testing.T) {
+	start := month(2025, time.January)
+	trialEnd := currentMonth().AddDate(1, 0, 0)
+	subscriptions := []*domain.Subscription{
+		{Id: "monthly", Price: 300, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: start},
+		{Id: "yearly", Price: 1200, Currency: "RUB", BillingPeriod: domain.BillingPeriodYearly, UserID: "user", StartDate: start},
+		{Id: "usd", Price: 10, Currency: "USD", BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: start},
+		{
+			Id: "paused", Price: 500, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: start,
+			Status: domain.StatusPaused, Pauses: []domain.Pause{{StartDate: month(2025, time.February)}},
+		},
+		{
+			Id: "trial", Price: 700, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, UserID: "user", StartDate: start,
+			TrialEndDate: &trialEnd,
+		},
+		{Id: "other", Price: 900, Currency: "RUB", BillingPeriod: domain.BillingPeriodMonthly, UserID: "other", StartDate: start},
+	}
+	s := &SubscriptionSvc{
+		subscriptionRepo: &periodRepo{subscriptions: subscriptions},
+		exchangeRates:    exchange.NewStaticRates("RUB", map[string]float64{"USD": 81.5}),
+		defaultCurrency:  "RUB",
+	}
+
+	output, err := s.GetUserMonthlySpend(userContext("user"), "user", nil)
+	if err != nil {
+		t.Fatalf("GetUserMonthlySpend() error = %v", err)
+	}
+	if output.Currency != "RUB" || output.Subscriptions != 3 {
+		t.Errorf("currency = %s, subscriptions = %d, want RUB and 3", output.Currency, output.Subscriptions)
+	}
+	if want := 300 + 1200/12 + 815; output.MonthlySpend != want {
+		t.Errorf("monthly spend = %d, want %d", output.MonthlySpend, want)
+	}
+	want := []dto.CurrencyTotalOutput{
+		{Currency: "RUB", TotalPrice: 400, ConvertedPrice: 400},
+		{Currency: "USD", TotalPrice: 10, ConvertedPrice: 815},
+	}
+	if len(output.Breakdown) != len(want) {
+		t.Fatalf("breakdown = %+v, want %+v", output.Breakdown, want)
+	}
+	for i, total := range output.Breakdown {
+		if *total != want[i] {
+			t.Errorf("breakdown[%d] = %+v, want %+v", i, *total, want[i])
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/repository/models"
+	"github.com/scmbr/subscription-aggregator/internal/service/dto"
+	"github.com/scmbr/subscription-aggregator/internal/webhook"
+)
+
+const (
+	// webhookSecretPrefix starts every generated signing secret.
+	webhookSecretPrefix = "whsec_"
+	// deliveryBatchSize deliveries are claimed at a time and sent one by
+	// one, which must fit within deliveryLease.
+	deliveryBatchSize = 20
+	deliveryLease     = 10 * time.Minute
+)
+
+type WebhookSvc struct {
+	webhookRepo repository.WebhookRepository
+	sender      webhook.Sender
+	retry       domain.RetryPolicy
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, sender webhook.Sender, retry domain.RetryPolicy) *WebhookSvc {
+	return &WebhookSvc{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		retry:       retry,
+	}
+}
+
+func (s *WebhookSvc) CreateWebhook(ctx context.Context, input *dto.CreateWebhookInput) (*dto.CreateWebhookOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var secret string
+	if input.Secret != nil {
+		secret = *input.Secret
+	} else {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+	eventTypes := make([]domain.EventType, 0, len(input.EventTypes))
+	for _, t := range input.EventTypes {
+		eventTypes = append(eventTypes, domain.EventType(t))
+	}
+	endpoint, err := domain.NewWebhookEndpoint(uuid.NewString(), input.URL, secret, eventTypes)
+	if err != nil {
+		return nil, ErrInvalidWebhook
+	}
+	if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return &dto.CreateWebhookOutput{
+		WebhookOutput: webhookToOutput(endpoint),
+		Secret:        secret,
+	}, nil
+}
+
+func (s *WebhookSvc) GetAllWebhooks(ctx context.Context) ([]*dto.WebhookOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	endpoints, err := s.webhookRepo.GetEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]*dto.WebhookOutput, 0, len(endpoints))
+	for _, e := range endpoints {
+		endpoint := webhookToOutput(e)
+		output = append(output, &endpoint)
+	}
+	return output, nil
+}
+
+// DeleteWebhook deletes the endpoint together with its delivery log.
+func (s *WebhookSvc) DeleteWebhook(ctx context.Context, id string) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.DeleteEndpoint(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the delivery log of an endpoint, newest
+// first.
+func (s *WebhookSvc) GetWebhookDeliveries(ctx context.Context, id string, input dto.GetWebhookDeliveriesInput) (*dto.GetWebhookDeliveriesOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if input.Status != nil && !domain.DeliveryStatus(*input.Status).IsValid() {
+		return nil, ErrInvalidDeliveryStatus
+	}
+	if _, err := s.webhookRepo.GetEndpointById(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	deliveries, total, err := s.webhookRepo.GetDeliveries(ctx, models.WebhookDeliveryFilter{
+		EndpointID: id,
+		Status:     input.Status,
+	}, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]*dto.WebhookDeliveryOutput, 0, len(deliveries))
+	for _, d := range deliveries {
+		output = append(output, deliveryToOutput(d))
+	}
+	return &dto.GetWebhookDeliveriesOutput{
+		Total:      total,
+		Deliveries: output,
+	}, nil
+}
+
+// RetryWebhookDelivery queues a dead-lettered delivery again with a fresh
+// set of attempts.
+func (s *WebhookSvc) RetryWebhookDelivery(ctx context.Context, id, deliveryID string) (*dto.WebhookDeliveryOutput, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.GetDeliveryById(ctx, id, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if err := delivery.Redeliver(time.Now().UTC()); err != nil {
+		return nil, ErrDeliveryNotDead
+	}
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return deliveryToOutput(delivery), nil
+}
+
+// Publish queues a delivery of the event to each endpoint subscribed to its
+// type. The deliveries are sent by DeliverWebhooks. An event published again
+// is not delivered twice to the same endpoint.
+func (s *WebhookSvc) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	endpoints, err := s.webhookRepo.GetEndpointsByEventType(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	deliveries := make([]*domain.WebhookDelivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, endpoint.NewDelivery(uuid.NewString(), event, now))
+	}
+	return s.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
+
+// DeliverWebhooks sends the deliveries that are due until none are left.
+// A failed attempt is retried with exponential backoff and the delivery is
+// dead-lettered once it runs out of attempts. It returns the number of
+// deliveries that succeeded.
+func (s *WebhookSvc) DeliverWebhooks(ctx context.Context) (int, error) {
+	endpoints := make(map[string]*domain.WebhookEndpoint)
+	delivered := 0
+	for {
+		deliveries, err := s.webhookRepo.ClaimDeliveries(ctx, time.Now().UTC(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+		for _, delivery := range deliveries {
+			endpoint, ok := endpoints[delivery.EndpointID]
+			if !ok {
+				endpoint, err = s.webhookRepo.GetEndpointById(ctx, delivery.EndpointID)
+				if errors.Is(err, repository.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return delivered, err
+				}
+				endpoints[delivery.EndpointID] = endpoint
+			}
+			sent, err := s.deliver(ctx, endpoint, delivery)
+			if err != nil {
+				return delivered, err
+			}
+			if sent {
+				delivered++
+			}
+		}
+		if len(deliveries) < deliveryBatchSize || ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+	}
+}
+
+// deliver makes an attempt of the delivery and stores its outcome.
+func (s *WebhookSvc) deliver(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) (bool, error) {
+	statusCode, err := s.sender.Send(ctx, endpoint, delivery, time.Now().UTC())
+	if ctx.Err() != nil {
+		// The lease expires and the attempt is made again by the next run.
+		return false, ctx.Err()
+	}
+	now := time.Now().UTC()
+	if err != nil {
+		var code *int
+		if statusCode != 0 {
+			code = &statusCode
+		}
+		delivery.Fail(now, code, err.Error(), s.retry)
+	} else {
+		delivery.Succeed(now, statusCode)
+	}
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return false, err
+	}
+	return delivery.Status == domain.DeliverySucceeded, nil
+}
+
+func webhookToOutput(endpoint *domain.WebhookEndpoint) dto.WebhookOutput {
+	eventTypes := make([]string, 0, len(endpoint.EventTypes))
+	for _, t := range endpoint.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return dto.WebhookOutput{
+		ID:         endpoint.Id,
+		URL:        endpoint.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
+
+func deliveryToOutput(delivery *domain.WebhookDelivery) *dto.WebhookDeliveryOutput {
+	var nextAttemptAt *time.Time
+	if delivery.Status == domain.DeliveryPending {
+		at := delivery.NextAttemptAt
+		nextAttemptAt = &at
+	}
+	return &dto.WebhookDeliveryOutput{
+		ID:             delivery.Id,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+// generateWebhookSecret returns a random signing secret.
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
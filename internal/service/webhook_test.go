@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+	"github.com/scmbr/subscription-aggregator/internal/repository"
+	"github.com/scmbr/subscription-aggregator/internal/webhook"
+)
+
+// deliveryQueue keeps deliveries in memory and hands out the pending ones
+// that are due, as ClaimDeliveries does.
+type deliveryQueue struct {
+	repository.WebhookRepository
+	endpoint   *domain.WebhookEndpoint
+	deliveries []*domain.WebhookDelivery
+}
+
+func (q *deliveryQueue) GetEndpointById(ctx context.Context, id string) (*domain.WebhookEndpoint, error) {
+	if id != q.endpoint.Id {
+		return nil, repository.ErrNotFound
+	}
+	return q.endpoint, nil
+}
+
+func (q *deliveryQueue) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	due := make([]*domain.WebhookDelivery, 0)
+	for _, delivery := range q.deliveries {
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (q *deliveryQueue) UpdateDelivery(ctx context.Context, input *domain.WebhookDelivery) error {
+	return nil
+}
+
+// webhookFixture queues a delivery to a test server answering with the
+// given statuses in turn and then with the last one.
+func webhookFixture(t *testing.T, statuses ...int) (*deliveryQueue, *domain.WebhookDelivery, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("whsec_test", r.Header, body, time.Minute, time.Now()); err != nil {
+			t.Errorf("request %d: %v", n, err)
+		}
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	endpoint := &domain.WebhookEndpoint{Id: "endpoint", URL: server.URL, Secret: "whsec_test"}
+	delivery := endpoint.NewDelivery("delivery", &domain.OutboxEvent{
+		Id:      "event",
+		Type:    domain.EventSubscriptionCreated,
+		Payload: []byte(`{"id":"1"}`),
+	}, time.Now().UTC())
+	return &deliveryQueue{endpoint: endpoint, deliveries: []*domain.WebhookDelivery{delivery}}, delivery, &requests
+}
+
+func TestDeliverWebhooksRetriesServerErrors(t *testing.T) {
+	queue, delivery, requests := webhookFixture(t, http.StatusServiceUnavailable, http.StatusOK)
+	// No backoff, so that the retry is due on the next run.
+	svc := NewWebhookService(queue, webhook.NewClient(5*time.Second), domain.RetryPolicy{MaxAttempts: 3})
+
+	delivered, err := svc.DeliverWebhooks(context.Background())
+	if err != nil || delivered != 0 {
+		t.Fatalf("first run: DeliverWebhooks() = %d, %v, want 0, nil", delivered, err)
+	}
+	if delivery.Status != domain.DeliveryPending || delivery.Attempts != 1 ||
+		delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("after 503: status %s, attempts %d, last status %v", delivery.Status, delivery.Attempts, delivery.LastStatusCode)
+	}
+	delivered, err = svc.DeliverWebhooks(context.Background())
+	if err != nil || delivered != 1 {
+		t.Fatalf("second run: DeliverWebhooks() = %d, %v, want 1, nil", delivered, err)
+	}
+	if delivery.Status != domain.DeliverySucceeded || delivery.Attempts != 2 || requests.Load() != 2 {
+		t.Errorf("after 200: status %s, attempts %d, requests %d", delivery.Status, delivery.Attempts, requests.Load())
+	}
+}
+
+func TestDeliverWebhooksDeadLettersAfterMaxAttempts(t *testing.T) {
+	queue, delivery, requests := webhookFixture(t, http.StatusInternalServerError)
+	svc := NewWebhookService(queue, webhook.NewClient(5*time.Second), domain.RetryPolicy{MaxAttempts: 3})
+
+	for run := 0; run < 5; run++ {
+		if _, err := svc.DeliverWebhooks(context.Background()); err != nil {
+			t.Fatalf("run %d: DeliverWebhooks() error = %v", run, err)
+		}
+	}
+	if delivery.Status != domain.DeliveryDead || delivery.Attempts != 3 || requests.Load() != 3 {
+		t.Errorf("status %s, attempts %d, requests %d, want dead after 3 attempts", delivery.Status, delivery.Attempts, requests.Load())
+	}
+}
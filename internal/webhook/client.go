@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+// Sender delivers events to webhook endpoints.
+type Sender interface {
+	// Send makes a single attempt of the delivery. It returns the status
+	// code of the response, or 0 when none was received, and an error
+	// unless the endpoint answered with a 2xx status.
+	Send(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery, now time.Time) (int, error)
+}
+
+// maxResponseSize bounds how much of a response is read before the
+// connection is reused.
+const maxResponseSize = 64 << 10
+
+// Client posts signed deliveries over HTTP. Redirects are not followed, so a
+// moved endpoint fails until it is registered again.
+type Client struct {
+	http *http.Client
+}
+
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *Client) Send(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("webhookClient.Send: %w", err)
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "subscription-aggregator-webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhookClient.Send: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhookClient.Send: unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scmbr/subscription-aggregator/internal/domain"
+)
+
+func TestClientSendSignsDelivery(t *testing.T) {
+	endpoint := &domain.WebhookEndpoint{Id: "endpoint", Secret: "whsec_test"}
+	delivery := &domain.WebhookDelivery{
+		Id:        "delivery",
+		EventType: domain.EventSubscriptionCreated,
+		Payload:   []byte(`{"id":"1"}`),
+	}
+	now := time.Now().UTC()
+
+	var received http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	endpoint.URL = server.URL
+
+	statusCode, err := NewClient(5*time.Second).Send(context.Background(), endpoint, delivery, now)
+	if err != nil || statusCode != http.StatusNoContent {
+		t.Fatalf("Send() = %d, %v, want %d, nil", statusCode, err, http.StatusNoContent)
+	}
+	for name, want := range map[string]string{
+		"Content-Type": "application/json",
+		HeaderEvent:    "subscription.created",
+		HeaderDelivery: "delivery",
+	} {
+		if got := received.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	if err := Verify(endpoint.Secret, received, body, time.Minute, now); err != nil {
+		t.Errorf("Verify() of the sent delivery: %v", err)
+	}
+}
+
+func TestClientSendFailsOnUnexpectedStatus(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadRequest, http.StatusFound} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if status == http.StatusFound {
+				w.Header().Set("Location", "/moved")
+			}
+			w.WriteHeader(status)
+		}))
+		endpoint := &domain.WebhookEndpoint{URL: server.URL, Secret: "whsec_test"}
+		statusCode, err := NewClient(5*time.Second).Send(context.Background(), endpoint, &domain.WebhookDelivery{}, time.Now())
+		server.Close()
+		if err == nil || statusCode != status {
+			t.Errorf("Send() against %d = %d, %v, want %d and an error", status, statusCode, err, status)
+		}
+	}
+}
+
+func TestClientSendWithoutResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	statusCode, err := NewClient(time.Second).Send(context.Background(), &domain.WebhookEndpoint{URL: url}, &domain.WebhookDelivery{}, time.Now())
+	if err == nil || statusCode != 0 {
+		t.Errorf("Send() to a closed server = %d, %v, want 0 and an error", statusCode, err)
+	}
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSignature carries "sha256=" followed by the hex encoded
+	// HMAC-SHA256 of the timestamp, a dot and the body, keyed with the
+	// secret of the endpoint.
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderTimestamp carries the Unix time the delivery was signed at.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	// HeaderDelivery identifies the delivery, which keeps its ID across
+	// retries, so receivers can drop duplicates.
+	HeaderDelivery = "X-Webhook-Delivery"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("stale webhook timestamp")
+)
+
+// Sign returns the value of the signature header of a body sent at
+// timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery and rejects deliveries
+// signed more than tolerance away from now, which stops replays.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected := Sign(secret, timestamp, body)
+	signature := strings.TrimSpace(header.Get(HeaderSignature))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > tolerance || skew < -tolerance {
+		return ErrStaleTimestamp
+	}
+	return nil
+}
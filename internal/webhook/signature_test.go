@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":"1"}`)
+	signedAt := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	header := func(secret string, body []byte) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(signedAt.Unix(), 10))
+		h.Set(HeaderSignature, Sign(secret, signedAt.Unix(), body))
+		return h
+	}
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"valid", header(secret, body), body, signedAt.Add(time.Minute), nil},
+		{"other secret", header("whsec_other", body), body, signedAt, ErrInvalidSignature},
+		{"tampered body", header(secret, body), []byte(`{"id":"2"}`), signedAt, ErrInvalidSignature},
+		{"missing timestamp", http.Header{HeaderSignature: {Sign(secret, signedAt.Unix(), body)}}, body, signedAt, ErrInvalidSignature},
+		{"stale", header(secret, body), body, signedAt.Add(10 * time.Minute), ErrStaleTimestamp},
+		{"from the future", header(secret, body), body, signedAt.Add(-10 * time.Minute), ErrStaleTimestamp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(secret, tt.header, tt.body, 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+CREATE TABLE subscription_prices(
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    price integer NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, effective_from)
+);
+INSERT INTO subscription_prices (subscription_id, price, effective_from)
+SELECT id, price, start_date FROM subscriptions;
\ No newline at end of file
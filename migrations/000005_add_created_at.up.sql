@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+CREATE INDEX idx_subscriptions_created_at_id ON subscriptions(created_at, id);
\ No newline at end of file
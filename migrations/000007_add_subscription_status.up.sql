@@ -0,0 +1,9 @@
+ALTER TABLE subscriptions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD COLUMN cancelled_at TIMESTAMPTZ;
+CREATE TABLE subscription_pauses(
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    start_date TIMESTAMPTZ NOT NULL,
+    end_date TIMESTAMPTZ,
+    PRIMARY KEY (subscription_id, start_date)
+);
\ No newline at end of file
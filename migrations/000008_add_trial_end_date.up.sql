@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN trial_end_date TIMESTAMPTZ DEFAULT NULL;
+CREATE INDEX idx_subscriptions_trial_end_date ON subscriptions(trial_end_date) WHERE trial_end_date IS NOT NULL;
\ No newline at end of file
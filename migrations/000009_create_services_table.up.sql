@@ -0,0 +1,13 @@
+CREATE TABLE services(
+    id UUID PRIMARY KEY,
+    name VARCHAR(30) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    category VARCHAR(50) DEFAULT NULL,
+    default_price integer DEFAULT NULL,
+    logo_url TEXT DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX idx_services_name ON services(LOWER(name));
+ALTER TABLE subscriptions
+    ADD COLUMN service_id UUID DEFAULT NULL REFERENCES services(id) ON DELETE SET NULL;
+CREATE INDEX idx_subscriptions_service_id ON subscriptions(service_id);
\ No newline at end of file
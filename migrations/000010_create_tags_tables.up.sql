@@ -0,0 +1,10 @@
+CREATE TABLE tags(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+CREATE TABLE subscription_tags(
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+CREATE INDEX idx_subscription_tags_tag_id ON subscription_tags(tag_id);
\ No newline at end of file
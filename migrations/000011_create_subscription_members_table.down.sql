@@ -0,0 +1 @@
+DROP TABLE IF EXISTS subscription_members;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE subscription_members(
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    weight integer DEFAULT NULL CHECK (weight > 0),
+    fixed_amount integer DEFAULT NULL CHECK (fixed_amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, user_id),
+    CHECK ((weight IS NULL) <> (fixed_amount IS NULL))
+);
+CREATE INDEX idx_subscription_members_user_id ON subscription_members(user_id);
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE sent_notifications(
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    due_date DATE NOT NULL,
+    user_id UUID NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, kind, due_date)
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE outbox_events(
+    id UUID PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    subscription_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT NULL,
+    published_at TIMESTAMPTZ DEFAULT NULL
+);
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(occurred_at, id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_published_at ON outbox_events(published_at) WHERE published_at IS NOT NULL;
\ No newline at end of file
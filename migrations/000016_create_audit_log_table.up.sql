@@ -0,0 +1,23 @@
+CREATE TABLE audit_log(
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    actor VARCHAR(150) NOT NULL,
+    on_behalf_of VARCHAR(255) DEFAULT NULL,
+    request_id VARCHAR(255) DEFAULT NULL,
+    before_state JSONB DEFAULT NULL,
+    after_state JSONB DEFAULT NULL,
+    changes JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_audit_log_subscription_id ON audit_log(subscription_id, occurred_at);
+CREATE INDEX idx_audit_log_occurred_at ON audit_log(occurred_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor);
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
\ No newline at end of file
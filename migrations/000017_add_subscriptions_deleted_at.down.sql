@@ -0,0 +1,12 @@
+-- Without deleted_at soft-deleted subscriptions would come back as live ones,
+-- so the rollback refuses to run until they are restored or purged.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM subscriptions WHERE deleted_at IS NOT NULL) THEN
+        RAISE EXCEPTION 'soft-deleted subscriptions exist: restore or purge them before rolling back';
+    END IF;
+END;
+$$;
+DROP INDEX IF EXISTS idx_subscriptions_deleted_at;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
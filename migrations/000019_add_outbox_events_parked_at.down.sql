@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_events_unpublished;
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(occurred_at, id) WHERE published_at IS NULL;
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS parked_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE outbox_events
+    ADD COLUMN parked_at TIMESTAMPTZ DEFAULT NULL;
+DROP INDEX IF EXISTS idx_outbox_events_unpublished;
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(occurred_at, id) WHERE published_at IS NULL AND parked_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,40 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+)
+
+type key struct {
+	ID string `json:"i"`
+}
+
+func TestNewSignerRejectsEmptySecret(t *testing.T) {
+	if _, err := NewSigner(""); !errors.Is(err, ErrEmptySecret) {
+		t.Fatalf("NewSigner(\"\") error = %v, want %v", err, ErrEmptySecret)
+	}
+}
+
+func TestSignerDecode(t *testing.T) {
+	signer, err := NewSigner("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := signer.Encode(key{ID: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got key
+	if err := signer.Decode(token, &got); err != nil || got.ID != "a" {
+		t.Fatalf("Decode() = %+v, %v", got, err)
+	}
+
+	other, _ := NewSigner("other")
+	forged, _ := other.Encode(key{ID: "b"})
+	for _, token := range []string{forged, token + "x", "garbage"} {
+		if err := signer.Decode(token, &got); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Decode(%q) error = %v, want %v", token, err, ErrInvalidCursor)
+		}
+	}
+}
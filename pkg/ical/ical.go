@@ -0,0 +1,109 @@
+// Package ical encodes iCalendar (RFC 5545) calendars of all-day events.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	// maxLineLength is the maximum length of a content line in octets,
+	// excluding the line break.
+	maxLineLength = 75
+)
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event is an all-day event, repeating by RRule when it is set.
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Date        time.Time
+	Summary     string
+	Description string
+	// RRule is a recurrence rule without UNTIL, e.g. "FREQ=MONTHLY".
+	RRule string
+	// Until is the last day of the recurrence.
+	Until *time.Time
+	// ExDates are the recurrences that do not occur.
+	ExDates []time.Time
+}
+
+// Encode writes the calendar to w, escaping text values and folding long
+// lines.
+func Encode(w io.Writer, cal *Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		writeFolded(bw, s)
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + escape(cal.ProdID))
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escape(e.UID))
+		line("DTSTAMP:" + e.Stamp.UTC().Format(dateTimeFormat))
+		line("DTSTART;VALUE=DATE:" + e.Date.Format(dateFormat))
+		if e.RRule != "" {
+			rule := e.RRule
+			if e.Until != nil {
+				rule += ";UNTIL=" + e.Until.Format(dateFormat)
+			}
+			line("RRULE:" + rule)
+		}
+		if len(e.ExDates) > 0 {
+			dates := make([]string, 0, len(e.ExDates))
+			for _, d := range e.ExDates {
+				dates = append(dates, d.Format(dateFormat))
+			}
+			line("EXDATE;VALUE=DATE:" + strings.Join(dates, ","))
+		}
+		line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// writeFolded writes a content line, splitting it into lines of at most
+// maxLineLength octets continued with a leading space.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines lose one octet to the leading space.
+		limit = maxLineLength - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,122 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func folded(s string) string {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeFolded(w, s)
+	w.Flush()
+	return buf.String()
+}
+
+// unfold joins the lines of a folded content line as RFC 5545 section 3.1
+// describes.
+func unfold(s string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n")
+}
+
+func TestWriteFolded(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines int
+	}{
+		{name: "short", line: "SUMMARY:Netflix", lines: 1},
+		{name: "exactly 75 octets", line: strings.Repeat("a", 75), lines: 1},
+		{name: "76 octets", line: strings.Repeat("a", 76), lines: 2},
+		{name: "continuation lines hold 74 octets", line: strings.Repeat("a", 75+74+74), lines: 3},
+		{name: "multi-byte runes", line: "SUMMARY:" + strings.Repeat("Кинопоиск ", 20), lines: 6},
+		{name: "rune across the limit", line: strings.Repeat("a", 74) + "ё" + strings.Repeat("b", 10), lines: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := folded(tt.line)
+			if !strings.HasSuffix(got, "\r\n") {
+				t.Fatalf("%q does not end with CRLF", got)
+			}
+			lines := strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("got %d lines, want %d: %q", len(lines), tt.lines, lines)
+			}
+			for i, line := range lines {
+				if len(line) > maxLineLength {
+					t.Errorf("line %d is %d octets long", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a rune: %q", i, line)
+				}
+			}
+			if unfolded := unfold(got); unfolded != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestWriteFoldedMovesSplitRuneToNextLine(t *testing.T) {
+	got := folded(strings.Repeat("a", 74) + "ё")
+	want := strings.Repeat("a", 74) + "\r\n ё\r\n"
+	if got != want {
+		t.Errorf("folded = %q, want %q", got, want)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"Yandex Plus":         "Yandex Plus",
+		`C:\path`:             `C:\\path`,
+		"a;b":                 `a\;b`,
+		"a,b":                 `a\,b`,
+		"line\nbreak":         `line\nbreak`,
+		"line\r\nbreak":       `line\nbreak`,
+		`\;,` + "\n":          `\\\;\,\n`,
+		"price: 300, RUB; ok": `price: 300\, RUB\; ok`,
+	}
+	for in, want := range tests {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	until := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	err := Encode(&buf, &Calendar{
+		ProdID: "-//subscription-aggregator//EN",
+		Events: []Event{{
+			UID:     "id@subscriptions",
+			Stamp:   time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC),
+			Date:    time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+			Summary: "Netflix, 300 RUB",
+			RRule:   "FREQ=MONTHLY",
+			Until:   &until,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20250301T100000Z\r\n",
+		"DTSTART;VALUE=DATE:20250401\r\n",
+		"RRULE:FREQ=MONTHLY;UNTIL=20251201\r\n",
+		`SUMMARY:Netflix\, 300 RUB` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("calendar does not contain %q:\n%s", line, buf.String())
+		}
+	}
+}
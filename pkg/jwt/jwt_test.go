@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+func sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": AlgHS256, "kid": "test"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, testSecret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifierVerify(t *testing.T) {
+	keys, err := ParseKeySet([]byte(`{"keys":[{"kty":"oct","kid":"test","alg":"HS256","k":"` +
+		base64.RawURLEncoding.EncodeToString(testSecret) + `"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewVerifier(keys, "", "")
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			token: sign(t, map[string]interface{}{"sub": "user", "exp": now.Add(time.Hour).Unix()}),
+		},
+		{
+			name:    "no expiry",
+			token:   sign(t, map[string]interface{}{"sub": "user", "role": "admin"}),
+			wantErr: ErrMissingExpiry,
+		},
+		{
+			name:    "expired",
+			token:   sign(t, map[string]interface{}{"sub": "user", "exp": now.Add(-time.Hour).Unix()}),
+			wantErr: ErrExpiredToken,
+		},
+		{
+			name:    "no subject",
+			token:   sign(t, map[string]interface{}{"exp": now.Add(time.Hour).Unix()}),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "tampered signature",
+			token:   sign(t, map[string]interface{}{"sub": "user", "exp": now.Add(time.Hour).Unix()}) + "x",
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Key is a verification key loaded from a JSON Web Key Set.
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+type KeySet struct {
+	Keys []*Key
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+var ErrNoKeySource = errors.New("no key set configured")
+
+// LoadKeySet reads a JSON Web Key Set (RFC 7517) from a local file. Symmetric
+// ("oct") keys are used for HS256 and RSA public keys for RS256.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt.LoadKeySet: %w", err)
+	}
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwt.LoadKeySet: %w", err)
+	}
+	return keys, nil
+}
+
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := &KeySet{Keys: make([]*Key, 0, len(set.Keys))}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys.Keys = append(keys.Keys, key)
+	}
+	if len(keys.Keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	return keys, nil
+}
+
+func parseKey(jwk jsonWebKey) (*Key, error) {
+	switch jwk.Kty {
+	case "oct":
+		if jwk.Alg != "" && jwk.Alg != AlgHS256 {
+			return nil, fmt.Errorf("unsupported algorithm %q", jwk.Alg)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+		if err != nil || len(secret) == 0 {
+			return nil, errors.New("invalid secret")
+		}
+		return &Key{ID: jwk.Kid, Algorithm: AlgHS256, secret: secret}, nil
+	case "RSA":
+		if jwk.Alg != "" && jwk.Alg != AlgRS256 {
+			return nil, fmt.Errorf("unsupported algorithm %q", jwk.Alg)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 {
+			return nil, errors.New("invalid exponent")
+		}
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return &Key{ID: jwk.Kid, Algorithm: AlgRS256, publicKey: publicKey}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}